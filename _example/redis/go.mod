@@ -0,0 +1,10 @@
+module github.com/diamondburned/arikawa/_example/redis
+
+go 1.13
+
+require (
+	github.com/diamondburned/arikawa v0.0.0
+	github.com/go-redis/redis/v7 v7.4.0
+)
+
+replace github.com/diamondburned/arikawa => ../..
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/diamondburned/arikawa/handler"
+	"github.com/diamondburned/arikawa/state"
+	"github.com/go-redis/redis/v7"
+)
+
+// To run, do `BOT_TOKEN="TOKEN HERE" REDIS_ADDR="localhost:6379" go run .`
+// This example has its own go.mod for go-redis, so run it in this directory.
+
+// RedisBackend implements state.HashBackend with go-redis.
+type RedisBackend struct {
+	*redis.Client
+}
+
+var _ state.HashBackend = (*RedisBackend)(nil)
+
+func (r RedisBackend) HGet(key, field string) ([]byte, error) {
+	b, err := r.Client.HGet(key, field).Bytes()
+	if err == redis.Nil {
+		return nil, state.ErrStoreNotFound
+	}
+	return b, err
+}
+
+func (r RedisBackend) HGetAll(key string) (map[string][]byte, error) {
+	all, err := r.Client.HGetAll(key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var m = make(map[string][]byte, len(all))
+	for field, v := range all {
+		m[field] = []byte(v)
+	}
+
+	return m, nil
+}
+
+func (r RedisBackend) HSet(key, field string, value []byte) error {
+	return r.Client.HSet(key, field, value).Err()
+}
+
+func (r RedisBackend) HDel(key string, fields ...string) error {
+	return r.Client.HDel(key, fields...).Err()
+}
+
+func (r RedisBackend) Expire(key string, ttl time.Duration) error {
+	return r.Client.Expire(key, ttl).Err()
+}
+
+func main() {
+	var token = os.Getenv("BOT_TOKEN")
+	if token == "" {
+		log.Fatalln("No $BOT_TOKEN given.")
+	}
+
+	var client = redis.NewClient(&redis.Options{
+		Addr: os.Getenv("REDIS_ADDR"),
+	})
+	defer client.Close()
+
+	store := state.NewHashStore(RedisBackend{client}, &state.HashStoreOptions{
+		Prefix:      "arikawa:",
+		TTL:         24 * time.Hour,
+		MaxMessages: 50,
+	})
+
+	s, err := state.NewWithStore("Bot "+token, store)
+	if err != nil {
+		log.Fatalln("Session failed:", err)
+	}
+
+	// Make a pre-handler, so the message is still in the store.
+	s.PreHandler = handler.New()
+	s.PreHandler.Synchronous = true
+	s.PreHandler.AddHandler(func(c *gateway.MessageDeleteEvent) {
+		// Messages are kept in Redis, even across restarts.
+		m, err := s.Message(c.ChannelID, c.ID)
+		if err != nil {
+			log.Println("Not found:", c.ID)
+		} else {
+			log.Println(m.Author.Username, "deleted", m.Content)
+		}
+	})
+
+	if err := s.Open(); err != nil {
+		log.Fatalln("Failed to connect:", err)
+	}
+	defer s.Close()
+
+	u, err := s.Me()
+	if err != nil {
+		log.Fatalln("Failed to get myself:", err)
+	}
+
+	log.Println("Started as", u.Username)
+
+	// Block forever.
+	select {}
+}
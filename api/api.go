@@ -68,11 +68,17 @@ type Session struct {
 
 func (s *Session) InjectRequest(r httpdriver.Request) error {
 	r.AddHeader(http.Header{
-		"Authorization":         {s.Token},
 		"User-Agent":            {s.UserAgent},
 		"X-RateLimit-Precision": {"millisecond"},
 	})
 
+	// Webhooks can be executed without a token.
+	if s.Token != "" {
+		r.AddHeader(http.Header{
+			"Authorization": {s.Token},
+		})
+	}
+
 	// Rate limit stuff
 	return s.Limiter.Acquire(r.GetContext(), r.GetPath())
 }
@@ -3,7 +3,11 @@ package api
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/diamondburned/arikawa/utils/httputil"
 )
 
 func TestContext(t *testing.T) {
@@ -18,3 +22,39 @@ func TestContext(t *testing.T) {
 		t.Fatal("Unexpected error:", err)
 	}
 }
+
+func TestHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{
+			"code": 50035,
+			"message": "Invalid Form Body",
+			"errors": {
+				"embed": {"fields": {"0": {"name": {"_errors": [
+					{"code": "BASE_TYPE_REQUIRED", "message": "This field is required"}
+				]}}}}
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	old := EndpointChannels
+	EndpointChannels = srv.URL + APIPath + "/channels/"
+	defer func() { EndpointChannels = old }()
+
+	_, err := NewClient("").Message(1, 2)
+
+	var herr *httputil.HTTPError
+	if !errors.As(err, &herr) {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	if herr.Status != http.StatusBadRequest || herr.Code != httputil.CodeInvalidFormBody {
+		t.Fatal("Unexpected status or code:", herr.Status, herr.Code)
+	}
+
+	fields := herr.FieldErrors()
+	if errs := fields["embed.fields.0.name"]; len(errs) != 1 || errs[0].Code != "BASE_TYPE_REQUIRED" {
+		t.Fatal("Unexpected field errors:", fields)
+	}
+}
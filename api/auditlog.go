@@ -0,0 +1,71 @@
+package api
+
+import (
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/utils/httputil"
+)
+
+// AuditLogData filters the entries of an audit log. All fields are optional.
+//
+// https://discord.com/developers/docs/resources/audit-log#get-guild-audit-log-query-string-parameters
+type AuditLogData struct {
+	// UserID filters the log for actions made by a user.
+	UserID discord.Snowflake `schema:"user_id,omitempty"`
+	// ActionType filters the log for a type of entry.
+	ActionType discord.AuditLogEvent `schema:"action_type,omitempty"`
+	// Before filters the log for entries older than the given entry ID.
+	Before discord.Snowflake `schema:"before,omitempty"`
+	// Limit is how many entries are returned, 1-100; 0 defaults to 50.
+	Limit uint `schema:"limit,omitempty"`
+}
+
+// AuditLog returns a page of the guild's audit log, from the newest entry to
+// the oldest. This requires the PermissionViewAuditLog permission.
+func (c *Client) AuditLog(
+	guildID discord.Snowflake, data AuditLogData) (*discord.AuditLog, error) {
+
+	switch {
+	case data.Limit == 0:
+		data.Limit = 50
+	case data.Limit > 100:
+		data.Limit = 100
+	}
+
+	var audit *discord.AuditLog
+	return audit, c.RequestJSON(
+		&audit, "GET",
+		EndpointGuilds+guildID.String()+"/audit-logs",
+		httputil.WithSchema(c, data),
+	)
+}
+
+// AuditLogEach calls fn with each page of the guild's audit log, from the
+// newest entry to the oldest, until fn returns false or all entries matching
+// the data are fetched. The data's Before is where the first page starts, and
+// its Limit is the size of each page, 100 if 0.
+func (c *Client) AuditLogEach(
+	guildID discord.Snowflake, data AuditLogData, fn func(*discord.AuditLog) bool) error {
+
+	if data.Limit == 0 || data.Limit > 100 {
+		data.Limit = 100
+	}
+
+	for {
+		l, err := c.AuditLog(guildID, data)
+		if err != nil {
+			return err
+		}
+
+		if len(l.Entries) == 0 || !fn(l) {
+			return nil
+		}
+
+		// There aren't any more entries to fetch.
+		if uint(len(l.Entries)) < data.Limit {
+			return nil
+		}
+
+		// Entries are newest first, so the last one is the oldest.
+		data.Before = l.Entries[len(l.Entries)-1].ID
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/discord"
+)
+
+func TestAuditLog(t *testing.T) {
+	var query string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+
+		fmt.Fprint(w, `{
+			"webhooks": [],
+			"users": [{"id": "2", "username": "mod"}],
+			"integrations": [],
+			"audit_log_entries": [{
+				"id": "10",
+				"target_id": "3",
+				"user_id": "2",
+				"action_type": 24,
+				"reason": "bad nick",
+				"changes": [
+					{"key": "nick", "old_value": "old", "new_value": "new"},
+					{"key": "$add", "new_value": [{"id": "4", "name": "muted"}]},
+					{"key": "afk_channel_id", "new_value": "5"},
+					{"key": "type", "new_value": 2}
+				]
+			}]
+		}`)
+	}))
+	defer srv.Close()
+
+	old := EndpointGuilds
+	EndpointGuilds = srv.URL + APIPath + "/guilds/"
+	defer func() { EndpointGuilds = old }()
+
+	client := NewClient("")
+
+	l, err := client.AuditLog(1, AuditLogData{
+		UserID:     2,
+		ActionType: discord.MemberUpdate,
+	})
+	if err != nil {
+		t.Fatal("Failed to get audit log:", err)
+	}
+
+	if query != "action_type=24&limit=50&user_id=2" {
+		t.Fatal("Unexpected query:", query)
+	}
+
+	if len(l.Entries) != 1 {
+		t.Fatal("Unexpected entries:", l.Entries)
+	}
+
+	e := l.Entries[0]
+	if e.ActionType != discord.MemberUpdate || e.TargetID != 3 || e.Reason != "bad nick" {
+		t.Fatalf("Unexpected entry: %+v", e)
+	}
+	if u := l.User(e.UserID); u == nil || u.Username != "mod" {
+		t.Fatal("Unexpected user:", u)
+	}
+
+	var oldNick, newNick string
+	if err := e.Changes[0].UnmarshalValues(&oldNick, &newNick); err != nil {
+		t.Fatal("Failed to unmarshal nick:", err)
+	}
+	if oldNick != "old" || newNick != "new" {
+		t.Fatal("Unexpected nicks:", oldNick, newNick)
+	}
+
+	tests := []struct {
+		old, new interface{}
+	}{
+		{"old", "new"},
+		{nil, []discord.Role{{ID: 4, Name: "muted"}}},
+		{nil, discord.Snowflake(5)},
+	}
+
+	for i, test := range tests {
+		o, n, err := e.Changes[i].Values()
+		if err != nil {
+			t.Fatal("Failed to decode change:", err)
+		}
+
+		if fmt.Sprint(o) != fmt.Sprint(test.old) || fmt.Sprint(n) != fmt.Sprint(test.new) {
+			t.Fatalf("Unexpected values of %s: %#v, %#v", e.Changes[i].Key, o, n)
+		}
+		if n != nil {
+			if _, ok := n.(discord.Snowflake); ok != (e.Changes[i].Key == discord.AuditGuildAFKChannelID) {
+				t.Fatalf("Unexpected type of %s: %T", e.Changes[i].Key, n)
+			}
+		}
+	}
+
+	// Keys with varying types are left raw.
+	if _, n, _ := e.Changes[3].Values(); string(n.(json.RawMessage)) != "2" {
+		t.Fatalf("Unexpected type value: %#v", n)
+	}
+}
+
+func TestAuditLogEach(t *testing.T) {
+	var requests int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		before, _ := strconv.Atoi(r.FormValue("before"))
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+
+		// A log of 250 entries with the IDs 1 to 250.
+		if before == 0 {
+			before = 251
+		}
+
+		var entries []string
+		for id := before - 1; id > 0 && len(entries) < limit; id-- {
+			entries = append(entries, fmt.Sprintf(`{"id":"%d"}`, id))
+		}
+
+		fmt.Fprintf(w, `{"audit_log_entries":[%s]}`, strings.Join(entries, ","))
+	}))
+	defer srv.Close()
+
+	old := EndpointGuilds
+	EndpointGuilds = srv.URL + APIPath + "/guilds/"
+	defer func() { EndpointGuilds = old }()
+
+	client := NewClient("")
+
+	var ids []discord.Snowflake
+
+	err := client.AuditLogEach(1, AuditLogData{}, func(l *discord.AuditLog) bool {
+		for _, e := range l.Entries {
+			ids = append(ids, e.ID)
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatal("Failed to get audit log:", err)
+	}
+
+	if len(ids) != 250 || ids[0] != 250 || ids[249] != 1 {
+		t.Fatal("Unexpected entries:", len(ids))
+	}
+	if requests != 3 {
+		t.Fatal("Unexpected request count:", requests)
+	}
+}
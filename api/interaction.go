@@ -0,0 +1,124 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/utils/httputil"
+	"github.com/pkg/errors"
+)
+
+var EndpointInteractions = Endpoint + "interactions/"
+
+type InteractionResponseType uint8
+
+const (
+	// PongResponse acknowledges a PingInteraction.
+	PongResponse InteractionResponseType = 1
+	// MessageResponse replies with a new message.
+	MessageResponse InteractionResponseType = 4
+	// DeferredMessageResponse shows a loading state, to be replaced with
+	// EditInteractionResponse later.
+	DeferredMessageResponse InteractionResponseType = 5
+	// DeferredUpdateResponse acknowledges a component interaction without
+	// changing its message yet.
+	DeferredUpdateResponse InteractionResponseType = 6
+	// UpdateMessageResponse edits the message that the component is attached
+	// to.
+	UpdateMessageResponse InteractionResponseType = 7
+)
+
+type InteractionResponse struct {
+	Type InteractionResponseType  `json:"type"`
+	Data *InteractionResponseData `json:"data,omitempty"`
+}
+
+// InteractionResponseData is the message of an interaction response. For
+// UpdateMessageResponse, the omitted fields of the message are kept.
+type InteractionResponseData struct {
+	TTS     bool            `json:"tts,omitempty"`
+	Content string          `json:"content,omitempty"`
+	Embeds  []discord.Embed `json:"embeds,omitempty"`
+
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+
+	// Flags may only be discord.EphemeralMessage.
+	Flags discord.MessageFlags `json:"flags,omitempty"`
+
+	Components []discord.Component `json:"components,omitempty"`
+}
+
+func (data *InteractionResponseData) validate() error {
+	if data.AllowedMentions != nil {
+		if err := data.AllowedMentions.Verify(); err != nil {
+			return errors.Wrap(err, "AllowedMentions error")
+		}
+	}
+
+	if len(data.Embeds) > MaxEmbeds {
+		return errors.Errorf("Embeds slice length %d is over %d",
+			len(data.Embeds), MaxEmbeds)
+	}
+
+	for i, embed := range data.Embeds {
+		if err := embed.Validate(); err != nil {
+			return errors.Wrap(err, "Embed error at "+strconv.Itoa(i))
+		}
+	}
+
+	return nil
+}
+
+// RespondInteraction responds to the interaction. This has to be done within 3
+// seconds of receiving it, or the user sees that the interaction failed.
+func (c *Client) RespondInteraction(
+	interactionID discord.Snowflake, token string, resp InteractionResponse) error {
+
+	if resp.Data != nil {
+		if err := resp.Data.validate(); err != nil {
+			return err
+		}
+	}
+
+	return c.FastRequest(
+		"POST", EndpointInteractions+interactionID.String()+"/"+token+"/callback",
+		httputil.WithJSONBody(c, resp),
+	)
+}
+
+// EditInteractionResponse edits the message that the interaction was
+// responded with, which also replaces the loading state of a
+// DeferredMessageResponse.
+func (c *Client) EditInteractionResponse(
+	appID discord.Snowflake, token string,
+	data InteractionResponseData) (*discord.Message, error) {
+
+	if err := data.validate(); err != nil {
+		return nil, err
+	}
+
+	var msg *discord.Message
+	return msg, c.RequestJSON(
+		&msg, "PATCH",
+		EndpointWebhooks+appID.String()+"/"+token+"/messages/@original",
+		httputil.WithJSONBody(c, data),
+	)
+}
+
+// DeleteInteractionResponse deletes the message that the interaction was
+// responded with.
+func (c *Client) DeleteInteractionResponse(appID discord.Snowflake, token string) error {
+	return c.FastRequest(
+		"DELETE", EndpointWebhooks+appID.String()+"/"+token+"/messages/@original",
+	)
+}
+
+// FollowupInteraction sends another message for the interaction, which works
+// for 15 minutes after the interaction is received. Flags can't be sent this
+// way.
+func (c *Client) FollowupInteraction(
+	appID discord.Snowflake, token string,
+	data ExecuteWebhookData) (*discord.Message, error) {
+
+	return c.ExecuteWebhook(appID, token, true, data)
+}
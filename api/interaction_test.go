@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/diamondburned/arikawa/discord"
+)
+
+func TestRespondInteraction(t *testing.T) {
+	var path string
+	var body InteractionResponse
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error("Failed to decode body:", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	old := EndpointInteractions
+	EndpointInteractions = srv.URL + APIPath + "/interactions/"
+	defer func() { EndpointInteractions = old }()
+
+	client := NewClient("")
+
+	err := client.RespondInteraction(1, "token", InteractionResponse{
+		Type: UpdateMessageResponse,
+		Data: &InteractionResponseData{
+			Content: "Updated",
+			Components: []discord.Component{
+				discord.NewActionRow(
+					discord.NewButton(discord.PrimaryButton, "Again", "again"),
+				),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal("Failed to respond:", err)
+	}
+
+	if path != APIPath+"/interactions/1/token/callback" {
+		t.Fatal("Unexpected path:", path)
+	}
+
+	if body.Type != UpdateMessageResponse || body.Data == nil ||
+		body.Data.Components[0].Components[0].CustomID != "again" {
+
+		t.Fatalf("Unexpected body: %+v", body)
+	}
+
+	err = client.RespondInteraction(1, "token", InteractionResponse{
+		Type: MessageResponse,
+		Data: &InteractionResponseData{
+			AllowedMentions: &AllowedMentions{Users: make([]discord.Snowflake, 101)},
+		},
+	})
+	if err == nil {
+		t.Fatal("Expected error for invalid allowed mentions")
+	}
+}
@@ -21,15 +21,14 @@ func (c *Client) Members(
 
 	var mems []discord.Member
 	var after discord.Snowflake = 0
+	var unlimited = max == 0
 
-	const hardLimit int = 1000
+	const hardLimit uint = 1000
 
-	for fetch := uint(hardLimit); max > 0; fetch = uint(hardLimit) {
-		if max > 0 {
-			if fetch > max {
-				fetch = max
-			}
-			max -= fetch
+	for {
+		var fetch = hardLimit
+		if !unlimited && fetch > max {
+			fetch = max
 		}
 
 		m, err := c.MembersAfter(guildID, after, fetch)
@@ -39,11 +38,17 @@ func (c *Client) Members(
 		mems = append(mems, m...)
 
 		// There aren't any to fetch, even if this is less than max.
-		if len(mems) < hardLimit {
+		if len(m) == 0 || uint(len(m)) < fetch {
 			break
 		}
 
-		after = mems[hardLimit-1].User.ID
+		if !unlimited {
+			if max -= fetch; max == 0 {
+				break
+			}
+		}
+
+		after = m[len(m)-1].User.ID
 	}
 
 	return mems, nil
@@ -79,6 +84,36 @@ func (c *Client) MembersAfter(
 	)
 }
 
+// SearchGuildMembers returns up to limit members whose username or nickname
+// starts with the query, case-insensitively. The limit is 1-1000, and defaults
+// to 1 if 0.
+func (c *Client) SearchGuildMembers(
+	guildID discord.Snowflake, query string, limit uint) ([]discord.Member, error) {
+
+	if limit == 0 {
+		limit = 1
+	}
+
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	var param struct {
+		Query string `schema:"query"`
+		Limit uint   `schema:"limit"`
+	}
+
+	param.Query = query
+	param.Limit = limit
+
+	var mems []discord.Member
+	return mems, c.RequestJSON(
+		&mems, "GET",
+		EndpointGuilds+guildID.String()+"/members/search",
+		httputil.WithSchema(c, param),
+	)
+}
+
 // AnyMemberData, all fields are optional.
 type AnyMemberData struct {
 	Nick string `json:"nick,omitempty"`
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/diamondburned/arikawa/discord"
+)
+
+func TestSearchGuildMembers(t *testing.T) {
+	var path, query string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		query = r.URL.RawQuery
+
+		mems := []discord.Member{{User: discord.User{ID: 2, Username: "partial"}}}
+		if err := json.NewEncoder(w).Encode(mems); err != nil {
+			t.Error("Failed to encode members:", err)
+		}
+	}))
+	defer srv.Close()
+
+	old := EndpointGuilds
+	EndpointGuilds = srv.URL + APIPath + "/guilds/"
+	defer func() { EndpointGuilds = old }()
+
+	client := NewClient("")
+
+	mems, err := client.SearchGuildMembers(1, "part", 5000)
+	if err != nil {
+		t.Fatal("Failed to search members:", err)
+	}
+
+	if path != APIPath+"/guilds/1/members/search" || query != "limit=1000&query=part" {
+		t.Fatal("Unexpected request:", path, query)
+	}
+	if len(mems) != 1 || mems[0].User.ID != 2 {
+		t.Fatal("Unexpected members:", mems)
+	}
+}
@@ -1,41 +1,79 @@
 package api
 
 import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
 	"github.com/diamondburned/arikawa/discord"
 	"github.com/diamondburned/arikawa/utils/httputil"
+	"github.com/pkg/errors"
 )
 
-// Messages gets all mesesages, automatically paginating. Use with care, as
-// this could get as many as hundred thousands of messages, making a lot of
-// queries.
+// Messages gets the latest max messages in the channel, automatically
+// paginating. If max is 0, all messages are fetched. Use with care, as this
+// could get as many as hundred thousands of messages, making a lot of queries.
+// Messages are ordered from the newest to the oldest, like Discord returns
+// them.
 func (c *Client) Messages(channelID discord.Snowflake, max uint) ([]discord.Message, error) {
 	var msgs []discord.Message
-	var after discord.Snowflake = 0
 
-	const hardLimit int = 100
+	err := c.messagesPages(channelID, max, func(m []discord.Message) bool {
+		msgs = append(msgs, m...)
+		return true
+	})
 
-	for fetch := uint(hardLimit); max > 0; fetch = uint(hardLimit) {
-		if max > 0 {
-			if fetch > max {
-				fetch = max
-			}
-			max -= fetch
+	return msgs, err
+}
+
+// MessagesEach calls fn with each page of up to 100 messages, from the newest
+// to the oldest, until fn returns false or all messages are fetched. This
+// allows going through a channel's history without keeping all of it in
+// memory.
+func (c *Client) MessagesEach(
+	channelID discord.Snowflake, fn func([]discord.Message) bool) error {
+
+	return c.messagesPages(channelID, 0, fn)
+}
+
+func (c *Client) messagesPages(
+	channelID discord.Snowflake, max uint, fn func([]discord.Message) bool) error {
+
+	const hardLimit uint = 100
+
+	var before discord.Snowflake
+	var unlimited = max == 0
+
+	for {
+		var fetch = hardLimit
+		if !unlimited && fetch > max {
+			fetch = max
 		}
 
-		m, err := c.messagesRange(channelID, 0, after, 0, fetch)
+		m, err := c.messagesRange(channelID, before, 0, 0, fetch)
 		if err != nil {
-			return msgs, err
+			return err
 		}
-		msgs = append(msgs, m...)
 
-		if len(m) < hardLimit {
-			break
+		if len(m) == 0 || !fn(m) {
+			return nil
 		}
 
-		after = m[hardLimit-1].Author.ID
-	}
+		// There aren't any more messages to fetch.
+		if uint(len(m)) < fetch {
+			return nil
+		}
 
-	return msgs, nil
+		if !unlimited {
+			if max -= fetch; max == 0 {
+				return nil
+			}
+		}
+
+		// Messages are newest first, so the last one is the oldest.
+		before = m[len(m)-1].ID
+	}
 }
 
 // MessagesAround returns messages around the ID, with a limit of 1-100.
@@ -110,27 +148,89 @@ func (c *Client) SendMessage(
 	})
 }
 
+// EditMessage edits the content and the embed of a message. An empty content or
+// a nil embed is left unchanged. Use EditMessageComplex to change or clear other
+// fields.
 func (c *Client) EditMessage(
 	channelID, messageID discord.Snowflake, content string,
 	embed *discord.Embed, suppressEmbeds bool) (*discord.Message, error) {
 
-	var param struct {
-		Content string               `json:"content,omitempty"`
-		Embed   *discord.Embed       `json:"embed,omitempty"`
-		Flags   discord.MessageFlags `json:"flags,omitempty"`
-	}
+	var data = EditMessageData{Embed: embed}
 
-	param.Content = content
-	param.Embed = embed
+	if content != "" {
+		data.Content = &content
+	}
 	if suppressEmbeds {
-		param.Flags = discord.SuppressEmbeds
+		var flags = discord.SuppressEmbeds
+		data.Flags = &flags
+	}
+
+	return c.EditMessageComplex(channelID, messageID, data)
+}
+
+// EditMessageData is the data for EditMessageComplex. Each nil field is left
+// unchanged, while a field pointing to an empty value is cleared:
+//
+//    // Replace the content and remove all embeds.
+//    content := "Done."
+//    data := api.EditMessageData{Content: &content, Embeds: &[]discord.Embed{}}
+//
+type EditMessageData struct {
+	Content *string `json:"content,omitempty"`
+	// Embed, if not nil, replaces the embed. Use Embeds to clear it.
+	Embed  *discord.Embed   `json:"embed,omitempty"`
+	Embeds *[]discord.Embed `json:"embeds,omitempty"`
+	// Flags, if not nil, replaces the flags. Only discord.SuppressEmbeds can be
+	// set or cleared.
+	Flags *discord.MessageFlags `json:"flags,omitempty"`
+
+	AllowedMentions *AllowedMentions     `json:"allowed_mentions,omitempty"`
+	Components      *[]discord.Component `json:"components,omitempty"`
+}
+
+// Validate checks the data against Discord's limits, like SendMessageData's
+// Validate. This is called on EditMessageComplex.
+func (data *EditMessageData) Validate() error {
+	if data.Content != nil {
+		if n := utf8.RuneCountInString(*data.Content); n > MaxMessageContent {
+			return &discord.ErrOverbound{Count: n, Max: MaxMessageContent, Thing: "Content"}
+		}
+	}
+
+	if data.AllowedMentions != nil {
+		if err := data.AllowedMentions.Verify(); err != nil {
+			return errors.Wrap(err, "AllowedMentions error")
+		}
+	}
+
+	var embeds []discord.Embed
+	if data.Embeds != nil {
+		embeds = *data.Embeds
+	}
+
+	if err := verifyEmbeds(data.Embed, embeds); err != nil {
+		return err
+	}
+
+	return verifyEmbedsLength(data.Embed, embeds)
+}
+
+// EditMessageComplex edits a message. Only the fields set in data are changed,
+// so this could, for example, change the embeds without touching the content.
+// The message must be made by yourself, unless only the flags are changed,
+// which requires MANAGE_MESSAGES.
+func (c *Client) EditMessageComplex(
+	channelID, messageID discord.Snowflake, data EditMessageData) (*discord.Message, error) {
+
+	if err := data.Validate(); err != nil {
+		return nil, err
 	}
 
 	var msg *discord.Message
 	return msg, c.RequestJSON(
 		&msg, "PATCH",
 		EndpointChannels+channelID.String()+"/messages/"+messageID.String(),
-		httputil.WithJSONBody(c, param),
+		httputil.WithJSONBody(c, data),
 	)
 }
 
@@ -141,9 +241,107 @@ func (c *Client) DeleteMessage(channelID, messageID discord.Snowflake) error {
 		"/messages/"+messageID.String())
 }
 
-// DeleteMessages only works for bots. It can't delete messages older than 2
-// weeks, and will fail if tried. This endpoint requires MANAGE_MESSAGES.
-func (c *Client) DeleteMessages(channelID discord.Snowflake, messageIDs []discord.Snowflake) error {
+// BulkDeleteMaxAge is the age after which messages are deleted one by one in
+// DeleteMessages, as Discord only bulk deletes messages younger than 2 weeks.
+// It's a bit shorter than that, so messages about to expire aren't rejected.
+var BulkDeleteMaxAge = 14*24*time.Hour - time.Minute
+
+// DeleteMessagesError is returned by DeleteMessages when some messages couldn't
+// be deleted.
+type DeleteMessagesError struct {
+	// Failed has the IDs of the messages that weren't deleted.
+	Failed []discord.Snowflake
+	// Errors has the error of each failed request.
+	Errors []error
+}
+
+func (e *DeleteMessagesError) Error() string {
+	var strs = make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		strs[i] = err.Error()
+	}
+
+	return fmt.Sprintf("Failed to delete %d messages: %s",
+		len(e.Failed), strings.Join(strs, "; "))
+}
+
+// DeleteMessages deletes the messages, and returns how many were deleted.
+// Messages are bulk deleted 100 at a time, except for the ones older than
+// BulkDeleteMaxAge, which are deleted one by one. Duplicate IDs are ignored.
+// All messages are tried even if some fail, after which a
+// *DeleteMessagesError is returned. This endpoint requires MANAGE_MESSAGES.
+func (c *Client) DeleteMessages(
+	channelID discord.Snowflake, messageIDs []discord.Snowflake) (int, error) {
+
+	const hardLimit = 100
+
+	var cutoff = time.Now().Add(-BulkDeleteMaxAge)
+	var seen = make(map[discord.Snowflake]struct{}, len(messageIDs))
+	var recent, old []discord.Snowflake
+
+	for _, id := range messageIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		if id.Time().Before(cutoff) {
+			old = append(old, id)
+		} else {
+			recent = append(recent, id)
+		}
+	}
+
+	var deleted int
+	var failed DeleteMessagesError
+
+	fail := func(err error, ids ...discord.Snowflake) {
+		failed.Failed = append(failed.Failed, ids...)
+		failed.Errors = append(failed.Errors, err)
+	}
+
+	for len(recent) > 0 {
+		var chunk = recent
+		if len(chunk) > hardLimit {
+			chunk = chunk[:hardLimit]
+		}
+		recent = recent[len(chunk):]
+
+		// The bulk endpoint needs at least 2 messages.
+		if len(chunk) == 1 {
+			old = append(old, chunk[0])
+			break
+		}
+
+		if err := c.bulkDeleteMessages(channelID, chunk); err != nil {
+			fail(errors.Wrap(err, "Failed to bulk delete"), chunk...)
+			continue
+		}
+
+		deleted += len(chunk)
+	}
+
+	for _, id := range old {
+		if err := c.DeleteMessage(channelID, id); err != nil {
+			fail(errors.Wrap(err, "Failed to delete "+id.String()), id)
+			continue
+		}
+
+		deleted++
+	}
+
+	if len(failed.Errors) > 0 {
+		return deleted, &failed
+	}
+
+	return deleted, nil
+}
+
+// bulkDeleteMessages deletes 2-100 messages younger than 2 weeks at once. It
+// only works for bots.
+func (c *Client) bulkDeleteMessages(
+	channelID discord.Snowflake, messageIDs []discord.Snowflake) error {
+
 	var param struct {
 		Messages []discord.Snowflake `json:"messages"`
 	}
@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/diamondburned/arikawa/discord"
 	"github.com/diamondburned/arikawa/utils/httputil"
+	"github.com/pkg/errors"
 )
 
 // React adds a reaction to the message. This requires READ_MESSAGE_HISTORY (and
@@ -21,6 +22,37 @@ func (c *Client) Unreact(chID, msgID discord.Snowflake, emoji EmojiAPI) error {
 	return c.DeleteUserReaction(chID, msgID, 0, emoji)
 }
 
+// ReactAll adds the reactions to the message in order, one request each. All
+// reactions on a message share a rate limit bucket, so the requests are
+// spaced out by the rate limiter. If a reaction fails, ReactAll stops and
+// returns the error; the reactions before it stay added.
+func (c *Client) ReactAll(
+	channelID, messageID discord.Snowflake, emojis []EmojiAPI) error {
+
+	for _, emoji := range emojis {
+		if err := c.React(channelID, messageID, emoji); err != nil {
+			return errors.Wrap(err, "Failed to react with "+emoji)
+		}
+	}
+
+	return nil
+}
+
+// UnreactAll removes the client's own reactions from the message in order, the
+// same way as ReactAll. Use DeleteAllReactions to remove everyone's reactions
+// at once.
+func (c *Client) UnreactAll(
+	channelID, messageID discord.Snowflake, emojis []EmojiAPI) error {
+
+	for _, emoji := range emojis {
+		if err := c.Unreact(channelID, messageID, emoji); err != nil {
+			return errors.Wrap(err, "Failed to unreact "+emoji)
+		}
+	}
+
+	return nil
+}
+
 // Reactions returns all reactions. It will paginate automatically.
 func (c *Client) Reactions(
 	channelID, messageID discord.Snowflake,
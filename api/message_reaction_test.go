@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReactAll(t *testing.T) {
+	var added []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The path ends with /reactions/{emoji}/@me.
+		parts := strings.Split(r.URL.Path, "/")
+		emoji := parts[len(parts)-2]
+
+		if emoji == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.Method == "PUT" {
+			added = append(added, emoji)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	old := EndpointChannels
+	EndpointChannels = srv.URL + APIPath + "/channels/"
+	defer func() { EndpointChannels = old }()
+
+	client := NewClient("")
+
+	t.Run("all", func(t *testing.T) {
+		added = nil
+
+		if err := client.ReactAll(1, 2, []EmojiAPI{"a", "b", "c"}); err != nil {
+			t.Fatal("Failed to react:", err)
+		}
+
+		if strings.Join(added, ",") != "a,b,c" {
+			t.Fatal("Unexpected reactions:", added)
+		}
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		added = nil
+
+		err := client.ReactAll(1, 2, []EmojiAPI{"a", "bad", "c"})
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+
+		if !strings.Contains(err.Error(), "bad") {
+			t.Fatal("Error doesn't mention the emoji:", err)
+		}
+
+		if strings.Join(added, ",") != "a" {
+			t.Fatal("Unexpected reactions:", added)
+		}
+	})
+
+	t.Run("unreact", func(t *testing.T) {
+		if err := client.UnreactAll(1, 2, []EmojiAPI{"a", "b"}); err != nil {
+			t.Fatal("Failed to unreact:", err)
+		}
+
+		if err := client.UnreactAll(1, 2, []EmojiAPI{"bad"}); err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/discord"
+)
+
+// mockMessages serves a channel of n messages with the IDs 1 to n.
+func mockMessages(t *testing.T, n int) (requests *int, cleanup func()) {
+	requests = new(int)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+
+		before, _ := strconv.Atoi(r.FormValue("before"))
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+
+		if before == 0 {
+			before = n + 1
+		}
+
+		var msgs []discord.Message
+		for id := before - 1; id > 0 && len(msgs) < limit; id-- {
+			msgs = append(msgs, discord.Message{ID: discord.Snowflake(id)})
+		}
+
+		if err := json.NewEncoder(w).Encode(msgs); err != nil {
+			t.Error("Failed to encode messages:", err)
+		}
+	}))
+
+	old := EndpointChannels
+	EndpointChannels = srv.URL + APIPath + "/channels/"
+
+	return requests, func() {
+		EndpointChannels = old
+		srv.Close()
+	}
+}
+
+func TestMessages(t *testing.T) {
+	requests, cleanup := mockMessages(t, 250)
+	defer cleanup()
+
+	client := NewClient("")
+
+	t.Run("limited", func(t *testing.T) {
+		*requests = 0
+
+		msgs, err := client.Messages(1, 150)
+		if err != nil {
+			t.Fatal("Failed to get messages:", err)
+		}
+
+		if len(msgs) != 150 || msgs[0].ID != 250 || msgs[149].ID != 101 {
+			t.Fatal("Unexpected messages:", len(msgs))
+		}
+		if *requests != 2 {
+			t.Fatal("Unexpected request count:", *requests)
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		msgs, err := client.Messages(1, 0)
+		if err != nil {
+			t.Fatal("Failed to get messages:", err)
+		}
+
+		if len(msgs) != 250 || msgs[249].ID != 1 {
+			t.Fatal("Unexpected messages:", len(msgs))
+		}
+	})
+
+	t.Run("each", func(t *testing.T) {
+		var pages int
+
+		err := client.MessagesEach(1, func(m []discord.Message) bool {
+			pages++
+			return pages < 2
+		})
+		if err != nil {
+			t.Fatal("Failed to get messages:", err)
+		}
+
+		if pages != 2 {
+			t.Fatal("Unexpected page count:", pages)
+		}
+	})
+}
+
+func TestDeleteMessages(t *testing.T) {
+	var bulks []int
+	var singles []discord.Snowflake
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			var body struct {
+				Messages []discord.Snowflake `json:"messages"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error("Failed to decode body:", err)
+			}
+
+			if len(body.Messages) < 2 || len(body.Messages) > 100 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			bulks = append(bulks, len(body.Messages))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// The path ends with /messages/{id}.
+		id, _ := discord.ParseSnowflake(r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:])
+		if id == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		singles = append(singles, id)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	old := EndpointChannels
+	EndpointChannels = srv.URL + APIPath + "/channels/"
+	defer func() { EndpointChannels = old }()
+
+	client := NewClient("")
+
+	var now = time.Now()
+	var month = discord.NewSnowflakeFromTime(now.Add(-30 * 24 * time.Hour))
+
+	t.Run("chunks", func(t *testing.T) {
+		bulks, singles = nil, nil
+
+		var ids []discord.Snowflake
+		for i := 0; i < 201; i++ {
+			ids = append(ids, discord.NewSnowflakeFromTime(now.Add(-time.Duration(i)*time.Second)))
+		}
+		ids = append(ids, ids[0], month) // a duplicate and an old message
+
+		n, err := client.DeleteMessages(1, ids)
+		if err != nil {
+			t.Fatal("Failed to delete messages:", err)
+		}
+
+		if n != 202 {
+			t.Fatal("Unexpected deleted count:", n)
+		}
+		if fmt.Sprint(bulks) != "[100 100]" {
+			t.Fatal("Unexpected bulk deletes:", bulks)
+		}
+		if len(singles) != 2 || singles[0] != month || singles[1] != ids[200] {
+			t.Fatal("Unexpected single deletes:", singles)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		bulks, singles = nil, nil
+
+		n, err := client.DeleteMessages(1, []discord.Snowflake{1, month})
+
+		derr, ok := err.(*DeleteMessagesError)
+		if !ok {
+			t.Fatal("Unexpected error:", err)
+		}
+		if n != 1 || len(derr.Failed) != 1 || derr.Failed[0] != 1 {
+			t.Fatal("Unexpected result:", n, derr.Failed)
+		}
+	})
+}
+
+func TestEditMessageComplex(t *testing.T) {
+	var body string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+
+		if r.Method != "PATCH" || r.URL.Path != APIPath+"/channels/1/messages/2" {
+			t.Error("Unexpected request:", r.Method, r.URL.Path)
+		}
+
+		w.Write([]byte(`{"id":"2"}`))
+	}))
+	defer srv.Close()
+
+	old := EndpointChannels
+	EndpointChannels = srv.URL + APIPath + "/channels/"
+	defer func() { EndpointChannels = old }()
+
+	client := NewClient("")
+
+	edit := func(data EditMessageData) string {
+		if _, err := client.EditMessageComplex(1, 2, data); err != nil {
+			t.Fatal("Failed to edit message:", err)
+		}
+		return strings.TrimSpace(body)
+	}
+
+	// Nil fields are left out, and empty ones are cleared.
+	var content = ""
+	var flags = discord.SuppressEmbeds
+
+	if b := edit(EditMessageData{Content: &content, Embeds: &[]discord.Embed{}}); b != `{"content":"","embeds":[]}` {
+		t.Fatal("Unexpected body:", b)
+	}
+	if b := edit(EditMessageData{Flags: &flags}); b != `{"flags":4}` {
+		t.Fatal("Unexpected body:", b)
+	}
+
+	// EditMessage only sends what's given.
+	if _, err := client.EditMessage(1, 2, "new", nil, false); err != nil {
+		t.Fatal("Failed to edit message:", err)
+	}
+	if b := strings.TrimSpace(body); b != `{"content":"new"}` {
+		t.Fatal("Unexpected body:", b)
+	}
+
+	content = strings.Repeat("a", MaxMessageContent+1)
+	if _, err := client.EditMessageComplex(1, 2, EditMessageData{Content: &content}); err == nil {
+		t.Fatal("Expected error for content too long")
+	}
+}
@@ -46,6 +46,30 @@ type bucket struct {
 
 	reset     time.Time
 	lastReset time.Time // only for custom
+
+	limits atomic.Value // BucketLimits
+}
+
+// BucketLimits is the state of a bucket as of the last response.
+type BucketLimits struct {
+	// Bucket is the bucket hash given by Discord, which may be shared by
+	// multiple routes. It might be empty.
+	Bucket    string
+	Limit     uint
+	Remaining uint64
+	Reset     time.Time
+}
+
+// Limits returns the limits of the path's bucket as of the last response. False
+// is returned if no responses are received for the bucket yet.
+func (l *Limiter) Limits(path string) (BucketLimits, bool) {
+	b := l.getBucket(path, false)
+	if b == nil {
+		return BucketLimits{}, false
+	}
+
+	limits, ok := b.limits.Load().(BucketLimits)
+	return limits, ok
 }
 
 func NewLimiter(prefix string) *Limiter {
@@ -159,27 +183,58 @@ func (l *Limiter) Release(path string, headers http.Header) error {
 		// boolean
 		global = headers.Get("X-RateLimit-Global")
 
+		// string
+		hash = headers.Get("X-RateLimit-Bucket")
+
 		// seconds
+		limit      = headers.Get("X-RateLimit-Limit")
 		remaining  = headers.Get("X-RateLimit-Remaining")
 		reset      = headers.Get("X-RateLimit-Reset")
+		resetAfter = headers.Get("X-RateLimit-Reset-After")
+
+		// milliseconds
 		retryAfter = headers.Get("Retry-After")
 	)
 
+	// Store the limits for Limits once everything is parsed.
+	defer func() {
+		b.limits.Store(BucketLimits{
+			Bucket:    hash,
+			Limit:     b.limit,
+			Remaining: b.remaining,
+			Reset:     b.reset,
+		})
+	}()
+
 	switch {
 	case retryAfter != "":
-		i, err := strconv.Atoi(retryAfter)
+		ms, err := strconv.ParseFloat(retryAfter, 64)
 		if err != nil {
 			return errors.Wrap(err, "Invalid retryAfter "+retryAfter)
 		}
 
-		at := time.Now().Add(time.Duration(i) * time.Millisecond)
+		at := time.Now().Add(time.Duration(ms * float64(time.Millisecond)))
 
 		if global != "" { // probably true
 			atomic.StoreInt64(l.global, at.UnixNano())
 		} else {
+			// Make the next Acquire wait until the bucket resets.
 			b.reset = at
+			b.remaining = 0
+		}
+
+	case resetAfter != "":
+		// Prefer the relative reset, as it doesn't depend on the clocks being
+		// in sync.
+		secs, err := strconv.ParseFloat(resetAfter, 64)
+		if err != nil {
+			return errors.Wrap(err, "Invalid reset after "+resetAfter)
 		}
 
+		b.reset = time.Now().
+			Add(time.Duration(secs * float64(time.Second))).
+			Add(ExtraDelay)
+
 	case reset != "":
 		unix, err := strconv.ParseFloat(reset, 64)
 		if err != nil {
@@ -190,7 +245,7 @@ func (l *Limiter) Release(path string, headers http.Header) error {
 			Add(ExtraDelay)
 	}
 
-	if remaining != "" {
+	if remaining != "" && retryAfter == "" {
 		u, err := strconv.ParseUint(remaining, 10, 64)
 		if err != nil {
 			return errors.Wrap(err, "Invalid remaining "+remaining)
@@ -199,5 +254,14 @@ func (l *Limiter) Release(path string, headers http.Header) error {
 		b.remaining = u
 	}
 
+	if limit != "" {
+		u, err := strconv.ParseUint(limit, 10, 32)
+		if err != nil {
+			return errors.Wrap(err, "Invalid limit "+limit)
+		}
+
+		b.limit = uint(u)
+	}
+
 	return nil
 }
@@ -74,3 +74,51 @@ func TestRatelimitGlobal(t *testing.T) {
 		t.Error("Did not ratelimit correctly, got:", time.Since(sent))
 	}
 }
+
+// This test takes ~0.5 seconds to run
+func TestRatelimitRetryAfter(t *testing.T) {
+	l := NewLimiter("")
+
+	headers := http.Header{}
+	headers.Set("X-RateLimit-Remaining", "3")
+	headers.Set("Retry-After", "500")
+
+	sent := time.Now()
+
+	// This should exhaust the bucket regardless of the remaining header.
+	mockRequest(t, l, "/guilds/99/channels", headers)
+	mockRequest(t, l, "/guilds/99/channels", nil)
+
+	if d := time.Since(sent); d < 500*time.Millisecond || d >= time.Second {
+		t.Error("Did not ratelimit correctly, got:", d)
+	}
+}
+
+func TestRatelimitLimits(t *testing.T) {
+	l := NewLimiter("")
+
+	if _, ok := l.Limits("/guilds/99/channels"); ok {
+		t.Fatal("Unexpected limits before any request")
+	}
+
+	headers := http.Header{}
+	headers.Set("X-RateLimit-Bucket", "abcd1234")
+	headers.Set("X-RateLimit-Limit", "5")
+	headers.Set("X-RateLimit-Remaining", "4")
+	headers.Set("X-RateLimit-Reset-After", "2.5")
+
+	mockRequest(t, l, "/guilds/99/channels", headers)
+
+	limits, ok := l.Limits("/guilds/99/channels")
+	if !ok {
+		t.Fatal("Missing limits")
+	}
+
+	if limits.Bucket != "abcd1234" || limits.Limit != 5 || limits.Remaining != 4 {
+		t.Fatal("Unexpected limits:", limits)
+	}
+
+	if d := time.Until(limits.Reset); d < 2*time.Second || d > 3*time.Second {
+		t.Fatal("Unexpected reset:", d)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/diamondburned/arikawa/discord"
 	"github.com/diamondburned/arikawa/utils/httputil"
@@ -41,6 +42,10 @@ type AllowedMentions struct {
 	Parse []AllowedMentionType `json:"parse"`
 	Roles []discord.Snowflake  `json:"roles,omitempty"` // max 100
 	Users []discord.Snowflake  `json:"users,omitempty"` // max 100
+
+	// RepliedUser, if true, mentions the author of the message that is
+	// replied to. Refer to SendMessageData's Reference.
+	RepliedUser bool `json:"replied_user,omitempty"`
 }
 
 // AllowedMentionType is a constant that tells Discord what is allowed to parse
@@ -83,6 +88,16 @@ func (am AllowedMentions) Verify() error {
 	return nil
 }
 
+// MaxEmbeds is the maximum number of embeds a single message can have.
+const MaxEmbeds = 10
+
+// MaxMessageContent is the maximum number of characters in a message's content.
+const MaxMessageContent = 2000
+
+// MaxEmbedsLength is the maximum number of characters in all embeds of a
+// message combined, as counted by discord.Embed's Length.
+const MaxEmbedsLength = 6000
+
 // ErrEmptyMessage is returned if either a SendMessageData or an
 // ExecuteWebhookData has both an empty Content and no Embed(s).
 var ErrEmptyMessage = errors.New("Message is empty")
@@ -101,35 +116,107 @@ type SendMessageData struct {
 
 	TTS   bool           `json:"tts,omitempty"`
 	Embed *discord.Embed `json:"embed,omitempty"`
+	// Embeds holds multiple embeds, up to 10. It may be used alongside Embed.
+	Embeds []discord.Embed `json:"embeds,omitempty"`
 
 	Files []SendMessageFile `json:"-"`
 
 	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+
+	// Reference, if not nil, makes the message a reply to the referenced
+	// message. Only MessageID is required.
+	Reference *discord.MessageReference `json:"message_reference,omitempty"`
+
+	// Components are the action rows of buttons and select menus to attach.
+	// The message still needs a content, an embed or a file.
+	Components []discord.Component `json:"components,omitempty"`
 }
 
 func (data *SendMessageData) WriteMultipart(c json.Driver, body *multipart.Writer) error {
 	return writeMultipart(c, body, data, data.Files)
 }
 
-func (c *Client) SendMessageComplex(
-	channelID discord.Snowflake, data SendMessageData) (*discord.Message, error) {
+// Validate checks the data against Discord's limits, including the lengths of
+// the content and the embeds, so it wouldn't be rejected by the API.
+// SendMessageComplex only does the basic checks, so callers that want a clearer
+// error than the API's should call this first.
+func (data *SendMessageData) Validate() error {
+	if err := data.verify(); err != nil {
+		return err
+	}
 
-	if data.Content == "" && data.Embed == nil && len(data.Files) == 0 {
-		return nil, ErrEmptyMessage
+	if n := utf8.RuneCountInString(data.Content); n > MaxMessageContent {
+		return &discord.ErrOverbound{Count: n, Max: MaxMessageContent, Thing: "Content"}
+	}
+
+	return verifyEmbedsLength(data.Embed, data.Embeds)
+}
+
+// verify does the basic checks of SendMessageComplex.
+func (data *SendMessageData) verify() error {
+	if data.Content == "" && data.Embed == nil && len(data.Embeds) == 0 &&
+		len(data.Files) == 0 {
+
+		return ErrEmptyMessage
 	}
 
 	if data.AllowedMentions != nil {
 		if err := data.AllowedMentions.Verify(); err != nil {
-			return nil, errors.Wrap(err, "AllowedMentions error")
+			return errors.Wrap(err, "AllowedMentions error")
+		}
+	}
+
+	return verifyEmbeds(data.Embed, data.Embeds)
+}
+
+// verifyEmbeds validates each embed and the number of embeds. Embed may be nil.
+func verifyEmbeds(embed *discord.Embed, embeds []discord.Embed) error {
+	if embed != nil {
+		if err := embed.Validate(); err != nil {
+			return errors.Wrap(err, "Embed error")
 		}
 	}
 
-	if data.Embed != nil {
-		if err := data.Embed.Validate(); err != nil {
-			return nil, errors.Wrap(err, "Embed error")
+	if len(embeds) > MaxEmbeds {
+		return errors.Errorf("Embeds slice length %d is over %d",
+			len(embeds), MaxEmbeds)
+	}
+
+	for i, embed := range embeds {
+		if err := embed.Validate(); err != nil {
+			return errors.Wrap(err, "Embed error at "+strconv.Itoa(i))
 		}
 	}
 
+	return nil
+}
+
+// verifyEmbedsLength checks the total length of the embeds. Embed may be nil.
+func verifyEmbedsLength(embed *discord.Embed, embeds []discord.Embed) error {
+	var length int
+
+	if embed != nil {
+		length += embed.Length()
+	}
+
+	for _, embed := range embeds {
+		length += embed.Length()
+	}
+
+	if length > MaxEmbedsLength {
+		return &discord.ErrOverbound{Count: length, Max: MaxEmbedsLength, Thing: "Embeds"}
+	}
+
+	return nil
+}
+
+func (c *Client) SendMessageComplex(
+	channelID discord.Snowflake, data SendMessageData) (*discord.Message, error) {
+
+	if err := data.verify(); err != nil {
+		return nil, err
+	}
+
 	var URL = EndpointChannels + channelID.String() + "/messages"
 	var msg *discord.Message
 
@@ -165,6 +252,10 @@ type ExecuteWebhookData struct {
 
 	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
 
+	// Components can only be sent by webhooks owned by an application, such
+	// as interaction followups.
+	Components []discord.Component `json:"components,omitempty"`
+
 	// Optional fields specific to Webhooks.
 	Username  string      `json:"username,omitempty"`
 	AvatarURL discord.URL `json:"avatar_url,omitempty"`
@@ -193,6 +284,11 @@ func (c *Client) ExecuteWebhook(
 		}
 	}
 
+	if len(data.Embeds) > MaxEmbeds {
+		return nil, errors.Errorf("Embeds slice length %d is over %d",
+			len(data.Embeds), MaxEmbeds)
+	}
+
 	for i, embed := range data.Embeds {
 		if err := embed.Validate(); err != nil {
 			return nil, errors.Wrap(err, "Embed error at "+strconv.Itoa(i))
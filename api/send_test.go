@@ -144,6 +144,34 @@ func TestSendMessage(t *testing.T) {
 		err := send(data)
 		errMustContain(t, err, "Embed error")
 	})
+
+	t.Run("content too long", func(t *testing.T) {
+		var data = SendMessageData{
+			// Characters are counted, not bytes.
+			Content: strings.Repeat("é", MaxMessageContent+1),
+		}
+
+		err := data.Validate()
+		errMustContain(t, err, "Content overbound: 2001 > 2000")
+
+		data.Content = strings.Repeat("é", MaxMessageContent)
+		if err := data.Validate(); err != nil {
+			t.Fatal("Unexpected error:", err)
+		}
+	})
+
+	t.Run("embeds too long", func(t *testing.T) {
+		// Each embed is valid, but not all of them combined.
+		var embed = discord.Embed{Description: spaces(2048)}
+
+		var data = SendMessageData{
+			Embed:  &embed,
+			Embeds: []discord.Embed{embed, embed},
+		}
+
+		err := data.Validate()
+		errMustContain(t, err, "Embeds overbound: 6144 > 6000")
+	})
 }
 
 func errMustContain(t *testing.T, err error, contains string) {
@@ -169,3 +197,12 @@ func mustMarshal(t *testing.T, v interface{}) string {
 	}
 	return string(j)
 }
+
+func TestSendMessageComplexEmbeds(t *testing.T) {
+	var client = NewClient("")
+
+	_, err := client.SendMessageComplex(0, SendMessageData{
+		Embeds: make([]discord.Embed, MaxEmbeds+1),
+	})
+	errMustContain(t, err, "Embeds slice length 11 is over 10")
+}
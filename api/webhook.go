@@ -1,8 +1,12 @@
 package api
 
 import (
+	"net/url"
+	"strings"
+
 	"github.com/diamondburned/arikawa/discord"
 	"github.com/diamondburned/arikawa/utils/httputil"
+	"github.com/pkg/errors"
 )
 
 var EndpointWebhooks = Endpoint + "webhooks/"
@@ -76,3 +80,51 @@ func (c *Client) DeleteWebhook(webhookID discord.Snowflake) error {
 func (c *Client) DeleteWebhookWithToken(webhookID discord.Snowflake, token string) error {
 	return c.FastRequest("DELETE", EndpointWebhooks+webhookID.String()+"/"+token)
 }
+
+// ErrInvalidWebhookURL is returned by ParseWebhookURL if the URL isn't a
+// webhook URL.
+var ErrInvalidWebhookURL = errors.New("Invalid webhook URL")
+
+// ParseWebhookURL parses the webhook ID and token from a webhook URL, such as
+// https://discord.com/api/webhooks/{id}/{token}.
+func ParseWebhookURL(webhookURL string) (discord.Snowflake, string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return 0, "", errors.Wrap(err, "Failed to parse webhook URL")
+	}
+
+	// [..., "webhooks", id, token]
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 3 || parts[len(parts)-3] != "webhooks" {
+		return 0, "", ErrInvalidWebhookURL
+	}
+
+	id, err := discord.ParseSnowflake(parts[len(parts)-2])
+	if err != nil {
+		return 0, "", errors.Wrap(err, "Invalid webhook ID")
+	}
+
+	return id, parts[len(parts)-1], nil
+}
+
+// ExecuteWebhook sends a message to the webhook without needing a bot token.
+// Refer to (*Client).ExecuteWebhook.
+func ExecuteWebhook(
+	webhookID discord.Snowflake,
+	token string, wait bool, data ExecuteWebhookData) (*discord.Message, error) {
+
+	return NewClient("").ExecuteWebhook(webhookID, token, wait, data)
+}
+
+// ExecuteWebhookURL is like ExecuteWebhook, but it takes the webhook URL
+// instead.
+func ExecuteWebhookURL(
+	webhookURL string, wait bool, data ExecuteWebhookData) (*discord.Message, error) {
+
+	id, token, err := ParseWebhookURL(webhookURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return ExecuteWebhook(id, token, wait, data)
+}
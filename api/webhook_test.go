@@ -0,0 +1,17 @@
+package api
+
+import "testing"
+
+func TestParseWebhookURL(t *testing.T) {
+	id, token, err := ParseWebhookURL("https://discord.com/api/webhooks/123456/abc-DEF_ghi")
+	if err != nil {
+		t.Fatal("Failed to parse:", err)
+	}
+	if id != 123456 || token != "abc-DEF_ghi" {
+		t.Fatal("Unexpected ID or token:", id, token)
+	}
+
+	if _, _, err := ParseWebhookURL("https://discord.com/api/channels/123456/abc"); err != ErrInvalidWebhookURL {
+		t.Fatal("Unexpected error:", err)
+	}
+}
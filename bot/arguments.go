@@ -5,12 +5,20 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/diamondburned/arikawa/api/rate"
 	"github.com/diamondburned/arikawa/bot/shellwords"
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
 )
 
 type argumentValueFn func(string) (reflect.Value, error)
 
+// argumentLookupFn looks up the entity that the parsed ID refers to.
+type argumentLookupFn func(
+	*Context, *gateway.MessageCreateEvent, discord.Snowflake) (reflect.Value, error)
+
 // Parser implements a Parse(string) method for data structures that can be
 // used as arguments.
 type Parser interface {
@@ -18,7 +26,8 @@ type Parser interface {
 }
 
 // Usager is used in place of the automatically parsed struct name for Parser
-// and other interfaces.
+// and other interfaces. For CustomParser and ManualParser, the usage should
+// describe all arguments, as the help won't add an ellipsis after it.
 type Usager interface {
 	Usage() string
 }
@@ -32,6 +41,18 @@ type ManualParser interface {
 	ParseContent([]string) error
 }
 
+// Arguments contains the arguments of a command as the user typed them, split
+// but not parsed and without the command names. A command may take it right
+// after the event, which doesn't change how the other arguments are parsed:
+//
+//    // !forward #channel some text
+//    func (c *Commands) Forward(
+//        m *gateway.MessageCreateEvent, args bot.Arguments, ch discord.Snowflake,
+//        text ...string) error
+//
+// In the example above, args would be []string{"<#123>", "some", "text"}.
+type Arguments []string
+
 // ArgumentParts implements ManualParseable, in case you want to parse arguments
 // manually. It borrows the library's argument parser.
 type ArgumentParts struct {
@@ -76,14 +97,17 @@ func (r ArgumentParts) Length() int {
 }
 
 // CustomParser has a CustomParse method, which would be passed in the full
-// message content with the prefix and command trimmed. This is used
-// for commands that require more advanced parsing than the default parser.
+// message content with the prefix and command trimmed, along with the spaces
+// around the rest. This is used for commands that require more advanced
+// parsing than the default parser.
 type CustomParser interface {
 	CustomParse(arguments string) error
 }
 
 // RawArguments implements the CustomParser interface, which sets all the
-// arguments into it as raw as it could.
+// arguments into it as raw as it could. Like any CustomParser, the spaces and
+// new lines at the start and the end are trimmed, which is what most commands
+// want. Use RawContent to keep them.
 type RawArguments string
 
 var _ CustomParser = (*RawArguments)(nil)
@@ -93,9 +117,130 @@ func (a *RawArguments) CustomParse(arguments string) error {
 	return nil
 }
 
+// RawRemainder is a special argument type that receives the raw content after
+// all preceding arguments, similarly to RawArguments. This is useful for
+// commands that take a few arguments followed by free-form text:
+//
+//    // ~tag add name  some *free form*  body
+//    func (c *Commands) Add(m *gateway.MessageCreateEvent, name string, body bot.RawRemainder) error
+//
+// Like other parsers that take all arguments, RawRemainder has to be the last
+// argument, and it marks the command as Variadic. The remainder may therefore
+// be empty.
+type RawRemainder string
+
+var _ CustomParser = (*RawRemainder)(nil)
+
+func (r *RawRemainder) CustomParse(remainder string) error {
+	*r = RawRemainder(remainder)
+	return nil
+}
+
+// RawContent is a special argument type that receives the content after the
+// command exactly as it was sent, so commands handling code can keep its
+// indentation. Only the prefix, the command name (including the names of its
+// subcommands) and the single space or new line after the name are removed;
+// everything else, including any further spaces and new lines at either end,
+// is kept:
+//
+//    // ~eval
+//    //     if true {
+//    //         fmt.Println("indented")
+//    //     }
+//    func (c *Commands) Eval(m *gateway.MessageCreateEvent, code bot.RawContent) error
+//
+// The content isn't split into words beforehand, so unbalanced quotes in it
+// don't cause parsing errors. RawContent has to be the only argument, and it
+// may be empty.
+type RawContent string
+
+var _ CustomParser = (*RawContent)(nil)
+
+func (c *RawContent) CustomParse(content string) error {
+	*c = RawContent(content)
+	return nil
+}
+
+// skipWords skips the first n words in s, the same way ParseArgs would split
+// them, and returns the rest with the surrounding spaces trimmed.
+func skipWords(s string, n int) string {
+	return strings.TrimSpace(s[wordsEnd(s, n):])
+}
+
+// rawContent skips the first n words in s like skipWords, but only the single
+// space or new line after the last word is removed.
+func rawContent(s string, n int) string {
+	var rest = s[wordsEnd(s, n):]
+	if n > 0 && rest != "" && isSpace(rest[0]) {
+		rest = rest[1:]
+	}
+	return rest
+}
+
+// wordsEnd returns the index right after the first n words in s.
+func wordsEnd(s string, n int) int {
+	var i int
+
+	for ; n > 0; n-- {
+		// Skip the spaces before the word.
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+
+		var quote byte
+
+	Word:
+		for ; i < len(s); i++ {
+			switch c := s[i]; {
+			case c == '\\' && quote != '\'':
+				i++ // skip the escaped character
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case isSpace(c):
+				break Word
+			}
+		}
+	}
+
+	if i > len(s) {
+		return len(s)
+	}
+
+	return i
+}
+
+// splitDelimiter splits s on the delimiter and trims the spaces around each
+// part. An empty s has no parts.
+func splitDelimiter(s, delimiter string) []string {
+	if s == "" {
+		return nil
+	}
+
+	var parts = strings.Split(s, delimiter)
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+
+	return parts
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
 // Argument is each argument in a method.
 type Argument struct {
 	String string
+
+	// Optional is true if the argument has a default value, which is used when
+	// the argument is omitted. Refer to (*CommandContext).SetDefaults.
+	Optional bool
+	// def is only used if Optional is true.
+	def string
 	// Rule: pointer for structs, direct for primitives
 	rtype reflect.Type
 
@@ -105,8 +250,15 @@ type Argument struct {
 
 	// if nil, then manual
 	fn     argumentValueFn
+	lookup argumentLookupFn // optional, called after fn
 	manual *reflect.Method
 	custom *reflect.Method
+	tagged *taggedStruct
+	flags  *flagStruct
+
+	// full is true if String describes all arguments that a custom, manual,
+	// tagged or flag argument takes, so the help doesn't add an ellipsis.
+	full bool
 }
 
 func (a *Argument) Type() reflect.Type {
@@ -147,11 +299,14 @@ func newArgument(t reflect.Type, variadic bool) (*Argument, error) {
 			t = t.Elem()
 		}
 
+		var usage, full = usagerOr(typeI, t.String())
+
 		return &Argument{
-			String:  t.String(),
+			String:  usage,
 			rtype:   t,
 			pointer: ptr,
 			custom:  &mt,
+			full:    full,
 		}, nil
 	}
 
@@ -163,11 +318,14 @@ func newArgument(t reflect.Type, variadic bool) (*Argument, error) {
 			t = t.Elem()
 		}
 
+		var usage, full = usagerOr(typeI, t.String())
+
 		return &Argument{
-			String:  t.String(),
+			String:  usage,
 			rtype:   t,
 			pointer: ptr,
 			manual:  &mt,
+			full:    full,
 		}, nil
 	}
 
@@ -204,6 +362,106 @@ func newArgument(t reflect.Type, variadic bool) (*Argument, error) {
 		}, nil
 	}
 
+	switch t {
+	// discord.Snowflake is an int64, so it has to be checked before the kinds.
+	case typeSnowflake:
+		return &Argument{
+			String: "id",
+			rtype:  t,
+			fn: func(s string) (reflect.Value, error) {
+				id, err := parseMention(s, "id or mention", "<@!", "<@&", "<@", "<#")
+				return reflect.ValueOf(id), err
+			},
+		}, nil
+
+	case typeUser:
+		return &Argument{
+			String: "@user",
+			rtype:  t,
+			fn: func(s string) (reflect.Value, error) {
+				id, err := parseMention(s, "user mention", "<@!", "<@")
+				return reflect.ValueOf(id), err
+			},
+			lookup: lookupUser,
+		}, nil
+
+	case typeChannel:
+		return &Argument{
+			String: "#channel",
+			rtype:  t,
+			fn: func(s string) (reflect.Value, error) {
+				id, err := parseMention(s, "channel mention", "<#")
+				return reflect.ValueOf(id), err
+			},
+			lookup: lookupChannel,
+		}, nil
+
+	case typeEmoji, reflect.PtrTo(typeEmoji):
+		return &Argument{
+			String: "emoji",
+			rtype:  t,
+			fn: func(s string) (reflect.Value, error) {
+				e, err := parseEmoji(s)
+				if err != nil {
+					return nilV, err
+				}
+				if t.Kind() == reflect.Ptr {
+					return reflect.ValueOf(&e), nil
+				}
+				return reflect.ValueOf(e), nil
+			},
+		}, nil
+	}
+
+	// Check if the type is a struct with flag or tagged fields.
+	if !variadic && typeI.Elem().Kind() == reflect.Struct {
+		flags, err := newFlagStruct(typeI.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		if flags != nil {
+			return &Argument{
+				String:  flags.usage(),
+				rtype:   typeI.Elem(),
+				pointer: ptr,
+				flags:   flags,
+				full:    true,
+			}, nil
+		}
+
+		tagged, err := newTaggedStruct(typeI.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		if tagged != nil {
+			return &Argument{
+				String:  tagged.usage(),
+				rtype:   typeI.Elem(),
+				pointer: ptr,
+				tagged:  tagged,
+				full:    true,
+			}, nil
+		}
+	}
+
+	// time.Duration is an int64, so it has to be checked before the kinds.
+	if t == typeDuration {
+		return &Argument{
+			String: "duration",
+			rtype:  t,
+			fn: func(s string) (reflect.Value, error) {
+				d, err := time.ParseDuration(s)
+				if err != nil {
+					return nilV, errors.New(
+						"expected duration such as 1h30m, got " + strconv.Quote(s))
+				}
+				return reflect.ValueOf(d), nil
+			},
+		}, nil
+	}
+
 	var fn argumentValueFn
 
 	switch t.Kind() {
@@ -214,31 +472,31 @@ func newArgument(t reflect.Type, variadic bool) (*Argument, error) {
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		fn = func(s string) (reflect.Value, error) {
-			i, err := strconv.ParseInt(s, 10, 64)
-			return quickRet(i, err, t)
+			i, err := strconv.ParseInt(s, 10, t.Bits())
+			return quickRet(i, numberError(err, "integer"), t)
 		}
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		fn = func(s string) (reflect.Value, error) {
-			u, err := strconv.ParseUint(s, 10, 64)
-			return quickRet(u, err, t)
+			u, err := strconv.ParseUint(s, 10, t.Bits())
+			return quickRet(u, numberError(err, "unsigned integer"), t)
 		}
 
 	case reflect.Float32, reflect.Float64:
 		fn = func(s string) (reflect.Value, error) {
-			f, err := strconv.ParseFloat(s, 64)
-			return quickRet(f, err, t)
+			f, err := strconv.ParseFloat(s, t.Bits())
+			return quickRet(f, numberError(err, "number"), t)
 		}
 
 	case reflect.Bool:
 		fn = func(s string) (reflect.Value, error) {
-			switch s {
-			case "True", "TRUE", "true", "T", "t", "yes", "y", "Y", "1":
-				return reflect.ValueOf(true), nil
-			case "False", "FALSE", "false", "F", "f", "no", "n", "N", "0":
-				return reflect.ValueOf(false), nil
+			switch strings.ToLower(s) {
+			case "true", "t", "yes", "y", "on", "1":
+				return reflect.ValueOf(true).Convert(t), nil
+			case "false", "f", "no", "n", "off", "0":
+				return reflect.ValueOf(false).Convert(t), nil
 			default:
-				return nilV, errors.New("invalid bool [true|false]")
+				return nilV, errors.New("expected yes or no, got " + strconv.Quote(s))
 			}
 		}
 	}
@@ -247,13 +505,126 @@ func newArgument(t reflect.Type, variadic bool) (*Argument, error) {
 		return nil, errors.New("invalid type: " + t.String())
 	}
 
+	var usage = t.String()
+	if t.Kind() == reflect.Bool {
+		usage = "[yes|no]"
+	}
+
 	return &Argument{
-		String: t.String(),
+		String: usage,
 		rtype:  t,
 		fn:     fn,
 	}, nil
 }
 
+// parseMention parses either a raw ID or a mention with one of the given
+// prefixes, such as "<#" for channels. Prefixes that are prefixes of others
+// must be given last.
+func parseMention(s, expected string, prefixes ...string) (discord.Snowflake, error) {
+	var raw = s
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) && strings.HasSuffix(s, ">") {
+			raw = s[len(prefix) : len(s)-1]
+			break
+		}
+	}
+
+	id, err := discord.ParseSnowflake(raw)
+	if err != nil || !id.Valid() {
+		return 0, errors.New("expected " + expected + ", got " + strconv.Quote(s))
+	}
+
+	return id, nil
+}
+
+// parseEmoji parses either a Unicode emoji or a custom one, formatted as
+// <:name:id>, or <a:name:id> if it's animated.
+func parseEmoji(s string) (discord.Emoji, error) {
+	if strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">") {
+		parts := strings.Split(s[1:len(s)-1], ":")
+		if len(parts) != 3 || (parts[0] != "" && parts[0] != "a") || parts[1] == "" {
+			return discord.Emoji{}, errors.New("invalid custom emoji " + strconv.Quote(s))
+		}
+
+		id, err := discord.ParseSnowflake(parts[2])
+		if err != nil || !id.Valid() {
+			return discord.Emoji{}, errors.New("invalid custom emoji ID in " + strconv.Quote(s))
+		}
+
+		return discord.Emoji{
+			ID:       id,
+			Name:     parts[1],
+			Animated: parts[0] == "a",
+		}, nil
+	}
+
+	if !isUnicodeEmoji(s) {
+		return discord.Emoji{}, errors.New("expected emoji, got " + strconv.Quote(s))
+	}
+
+	return discord.Emoji{Name: s}, nil
+}
+
+// isUnicodeEmoji returns whether s is a single Unicode emoji, including ones
+// made of several code points, such as flags, keycaps, skin tones and ZWJ
+// sequences. It's loose, so some sequences that aren't emojis pass.
+func isUnicodeEmoji(s string) bool {
+	// Keycaps start with a digit, # or *.
+	if len(s) > 1 && strings.ContainsRune("0123456789#*", rune(s[0])) {
+		return strings.HasSuffix(s, "\u20e3")
+	}
+
+	for i, r := range s {
+		switch {
+		case rate.EmojiRune(r):
+		case i > 0 && (r == '\ufe0e' || r == '\ufe0f'): // variation selectors
+		case i > 0 && r >= 0xe0020 && r <= 0xe007f: // tags, used in some flags
+		default:
+			return false
+		}
+	}
+
+	return s != ""
+}
+
+func lookupUser(
+	ctx *Context,
+	mc *gateway.MessageCreateEvent, id discord.Snowflake) (reflect.Value, error) {
+
+	// Try the mentions first, as they're already in the message.
+	for i := range mc.Mentions {
+		if mc.Mentions[i].ID == id {
+			return reflect.ValueOf(&mc.Mentions[i].User), nil
+		}
+	}
+
+	if mc.GuildID.Valid() {
+		if m, err := ctx.Member(mc.GuildID, id); err == nil {
+			return reflect.ValueOf(&m.User), nil
+		}
+	}
+
+	u, err := ctx.User(id)
+	if err != nil {
+		return nilV, errors.New("unknown user " + id.String())
+	}
+
+	return reflect.ValueOf(u), nil
+}
+
+func lookupChannel(
+	ctx *Context,
+	mc *gateway.MessageCreateEvent, id discord.Snowflake) (reflect.Value, error) {
+
+	c, err := ctx.Channel(id)
+	if err != nil {
+		return nilV, errors.New("unknown channel " + id.String())
+	}
+
+	return reflect.ValueOf(c), nil
+}
+
 func quickRet(v interface{}, err error, t reflect.Type) (reflect.Value, error) {
 	if err != nil {
 		return nilV, err
@@ -268,17 +639,39 @@ func quickRet(v interface{}, err error, t reflect.Type) (reflect.Value, error) {
 	return rv.Convert(t), nil
 }
 
-func fromUsager(typeI reflect.Type) string {
-	if typeI.Implements(typeIUsager) {
-		mt, ok := typeI.MethodByName("Usage")
-		if !ok {
-			panic("BUG: type IUsager does not implement Usage")
-		}
+// numberError replaces strconv's errors with something more readable to the
+// user. The returned error is nil if err is nil.
+func numberError(err error, expected string) error {
+	if err == nil {
+		return nil
+	}
 
-		vs := mt.Func.Call([]reflect.Value{reflect.New(typeI.Elem())})
-		return vs[0].String()
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		return errors.New(expected + " out of range")
 	}
 
+	return errors.New("expected " + expected)
+}
+
+func fromUsager(typeI reflect.Type) string {
 	s := strings.Split(typeI.String(), ".")
-	return s[len(s)-1]
+	usage, _ := usagerOr(typeI, s[len(s)-1])
+	return usage
+}
+
+// usagerOr returns the usage from the type's Usager implementation, or the
+// fallback if the type doesn't implement it. The boolean is true if the usage
+// is from Usager.
+func usagerOr(typeI reflect.Type, fallback string) (string, bool) {
+	if !typeI.Implements(typeIUsager) {
+		return fallback, false
+	}
+
+	mt, ok := typeI.MethodByName("Usage")
+	if !ok {
+		panic("BUG: type IUsager does not implement Usage")
+	}
+
+	vs := mt.Func.Call([]reflect.Value{reflect.New(typeI.Elem())})
+	return vs[0].String(), true
 }
@@ -0,0 +1,228 @@
+package bot
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/pkg/errors"
+)
+
+// FlagTag is the struct tag used for flag struct arguments.
+//
+// Flag struct arguments
+//
+// A command may take a pointer to a struct with flag fields as its last
+// argument, for command line style options. Flags are given as "--name value",
+// "--name=value" or, for bool fields, just "--name". A single dash works too,
+// which is how the short name from the short option is usually given. All
+// flags are optional. The other arguments, and all arguments after a "--", are
+// positional. They're set to the field of type Arguments, if the struct has
+// one, or are an error otherwise. Example:
+//
+//    type PurgeFlags struct {
+//        Count   int  `flag:"count,short=n"`
+//        Verbose bool `flag:"verbose,short=v"`
+//        Users   bot.Arguments
+//    }
+//
+//    // ~purge -n 50 --verbose @user1 @user2
+//    func (c *Commands) Purge(m *gateway.MessageCreateEvent, f *PurgeFlags) error
+//
+// Each field accepts the same types as a normal argument, except for parsers
+// that take all arguments. A struct can't have both flag and ArgumentTag
+// fields.
+const FlagTag = "flag"
+
+type flagStruct struct {
+	fields []flagField
+	// args is the index of the Arguments field, or -1 if there's none.
+	args     int
+	argsName string
+}
+
+type flagField struct {
+	Argument
+	name  string
+	short string
+	index int
+}
+
+// newFlagStruct reflects the struct type. Nil is returned if the struct has no
+// flag fields.
+func newFlagStruct(t reflect.Type) (*flagStruct, error) {
+	var flags = flagStruct{args: -1}
+	var hasArgs bool
+
+	for i := 0; i < t.NumField(); i++ {
+		var field = t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if _, ok := field.Tag.Lookup(ArgumentTag); ok {
+			hasArgs = true
+		}
+
+		if field.Type == typeArguments {
+			flags.args = i
+			flags.argsName = lowerFirstLetter(field.Name)
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup(FlagTag)
+		if !ok {
+			continue
+		}
+
+		var opts = strings.Split(tag, ",")
+		var name = opts[0]
+		if name == "" {
+			name = lowerFirstLetter(field.Name)
+		}
+
+		arg, err := newArgument(field.Type, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid field "+field.Name)
+		}
+		if arg.fn == nil {
+			return nil, errors.New("Field " + field.Name + " cannot be a manual parser")
+		}
+
+		var f = flagField{
+			Argument: *arg,
+			name:     name,
+			index:    i,
+		}
+
+		for _, opt := range opts[1:] {
+			switch {
+			case strings.HasPrefix(opt, "short="):
+				f.short = strings.TrimPrefix(opt, "short=")
+			default:
+				return nil, errors.New("Unknown option " + opt + " in field " + field.Name)
+			}
+		}
+
+		for _, other := range flags.fields {
+			if f.name == other.name || f.name == other.short ||
+				(f.short != "" && (f.short == other.name || f.short == other.short)) {
+
+				return nil, errors.New("Duplicate flag in field " + field.Name)
+			}
+		}
+
+		flags.fields = append(flags.fields, f)
+	}
+
+	if len(flags.fields) == 0 {
+		return nil, nil
+	}
+	if hasArgs {
+		return nil, errors.New("Struct " + t.Name() + " cannot have both flags and tagged arguments")
+	}
+
+	return &flags, nil
+}
+
+// usage generates the usage string from the fields.
+func (fs *flagStruct) usage() string {
+	var usages = make([]string, 0, len(fs.fields)+1)
+
+	for _, f := range fs.fields {
+		var usage = "--" + f.name
+		if f.short != "" {
+			usage = "-" + f.short + "|" + usage
+		}
+		if f.rtype.Kind() != reflect.Bool {
+			usage += " " + f.String
+		}
+
+		usages = append(usages, "["+usage+"]")
+	}
+
+	if fs.args >= 0 {
+		usages = append(usages, "["+fs.argsName+"...]")
+	}
+
+	return strings.Join(usages, " ")
+}
+
+func (fs *flagStruct) field(name string) *flagField {
+	for i, f := range fs.fields {
+		if f.name == name || (f.short != "" && f.short == name) {
+			return &fs.fields[i]
+		}
+	}
+	return nil
+}
+
+// isFlag returns whether the argument looks like a flag. Negative numbers
+// aren't flags.
+func isFlag(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-' && arg != "--" && !unicode.IsDigit(rune(arg[1]))
+}
+
+// parseFlags parses the arguments into v, which must be the struct value.
+func (ctx *Context) parseFlags(
+	mc *gateway.MessageCreateEvent,
+	fs *flagStruct, v reflect.Value, arguments []string) error {
+
+	var positional Arguments
+
+	for i := 0; i < len(arguments); i++ {
+		var arg = arguments[i]
+
+		if arg == "--" {
+			positional = append(positional, arguments[i+1:]...)
+			break
+		}
+
+		if !isFlag(arg) {
+			positional = append(positional, arg)
+			continue
+		}
+
+		var name = strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		var value string
+		var hasValue bool
+
+		if j := strings.IndexByte(name, '='); j >= 0 {
+			name, value, hasValue = name[:j], name[j+1:], true
+		}
+
+		f := fs.field(name)
+		if f == nil {
+			return errors.Errorf("unknown flag %q", name)
+		}
+
+		switch {
+		case hasValue:
+		case f.rtype.Kind() == reflect.Bool:
+			value = "true"
+		case i+1 < len(arguments):
+			i++
+			value = arguments[i]
+		default:
+			return errors.Errorf("flag %q expects a value", name)
+		}
+
+		fv, err := ctx.parseArgument(mc, &f.Argument, value)
+		if err != nil {
+			return errors.Wrapf(err, "flag %q", name)
+		}
+
+		v.Field(f.index).Set(fv)
+	}
+
+	if fs.args < 0 {
+		if len(positional) > 0 {
+			return errors.Errorf("unexpected argument %q", positional[0])
+		}
+		return nil
+	}
+
+	v.Field(fs.args).Set(reflect.ValueOf(positional))
+	return nil
+}
@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/pkg/errors"
+)
+
+// ArgumentTag is the struct tag used for tagged struct arguments.
+//
+// Tagged struct arguments
+//
+// A command may take a pointer to a struct with tagged fields as its last
+// argument. All arguments are then parsed as either "name=value" or, for bool
+// fields, "--name". Fields without the tag are ignored. Fields are required
+// unless the tag has the optional option. Example:
+//
+//    type PurgeOptions struct {
+//        Channel discord.Snowflake `arg:"channel"`
+//        Count   int               `arg:"count"`
+//        Verbose bool              `arg:"verbose,optional"`
+//    }
+//
+//    // ~purge channel=<#123> count=50 --verbose
+//    func (c *Commands) Purge(m *gateway.MessageCreateEvent, opts *PurgeOptions) error
+//
+// Each field accepts the same types as a normal argument, except for parsers
+// that take all arguments.
+const ArgumentTag = "arg"
+
+type taggedStruct struct {
+	fields []taggedField
+}
+
+type taggedField struct {
+	Argument
+	name     string
+	index    int
+	optional bool
+}
+
+// newTaggedStruct reflects the struct type. Nil is returned if the struct has no
+// tagged fields.
+func newTaggedStruct(t reflect.Type) (*taggedStruct, error) {
+	var tagged taggedStruct
+
+	for i := 0; i < t.NumField(); i++ {
+		var field = t.Field(i)
+
+		tag, ok := field.Tag.Lookup(ArgumentTag)
+		if !ok || field.PkgPath != "" {
+			continue
+		}
+
+		var opts = strings.Split(tag, ",")
+		var name = opts[0]
+		if name == "" {
+			name = lowerFirstLetter(field.Name)
+		}
+
+		arg, err := newArgument(field.Type, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid field "+field.Name)
+		}
+		if arg.fn == nil {
+			return nil, errors.New("Field " + field.Name + " cannot be a manual parser")
+		}
+
+		var f = taggedField{
+			Argument: *arg,
+			name:     name,
+			index:    i,
+		}
+
+		for _, opt := range opts[1:] {
+			switch opt {
+			case "optional":
+				f.optional = true
+			default:
+				return nil, errors.New("Unknown option " + opt + " in field " + field.Name)
+			}
+		}
+
+		tagged.fields = append(tagged.fields, f)
+	}
+
+	if len(tagged.fields) == 0 {
+		return nil, nil
+	}
+
+	return &tagged, nil
+}
+
+// usage generates the usage string from the fields.
+func (ts *taggedStruct) usage() string {
+	var usages = make([]string, len(ts.fields))
+
+	for i, f := range ts.fields {
+		var usage string
+		if f.rtype.Kind() == reflect.Bool {
+			usage = "--" + f.name
+		} else {
+			usage = f.name + "=" + f.String
+		}
+
+		if f.optional {
+			usage = "[" + usage + "]"
+		}
+
+		usages[i] = usage
+	}
+
+	return strings.Join(usages, " ")
+}
+
+func (ts *taggedStruct) field(name string) *taggedField {
+	for i, f := range ts.fields {
+		if f.name == name {
+			return &ts.fields[i]
+		}
+	}
+	return nil
+}
+
+// parseTagged parses the arguments into v, which must be the struct value.
+func (ctx *Context) parseTagged(
+	mc *gateway.MessageCreateEvent,
+	ts *taggedStruct, v reflect.Value, arguments []string) error {
+
+	var set = make(map[string]bool, len(ts.fields))
+
+	for _, arg := range arguments {
+		var name, value string
+		var flag bool
+
+		if strings.HasPrefix(arg, "--") {
+			name = arg[2:]
+			flag = true
+		} else if i := strings.IndexByte(arg, '='); i > 0 {
+			name = arg[:i]
+			value = arg[i+1:]
+		} else {
+			return errors.Errorf("expected name=value, got %q", arg)
+		}
+
+		f := ts.field(name)
+		if f == nil {
+			return errors.Errorf("unknown argument %q", name)
+		}
+
+		if flag {
+			if f.rtype.Kind() != reflect.Bool {
+				return errors.Errorf("argument %q expects a value", name)
+			}
+			value = "true"
+		}
+
+		fv, err := ctx.parseArgument(mc, &f.Argument, value)
+		if err != nil {
+			return errors.Wrapf(err, "argument %q", name)
+		}
+
+		v.Field(f.index).Set(fv)
+		set[name] = true
+	}
+
+	for _, f := range ts.fields {
+		if !f.optional && !set[f.name] {
+			return errors.Errorf("missing argument %q", f.name)
+		}
+	}
+
+	return nil
+}
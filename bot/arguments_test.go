@@ -4,6 +4,11 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/diamondburned/arikawa/state"
 )
 
 type mockParser string
@@ -21,18 +26,225 @@ func TestArguments(t *testing.T) {
 	testArgs(t, "string", "string")
 	testArgs(t, true, "true")
 	testArgs(t, false, "n")
+	testArgs(t, true, "ON")
+	testArgs(t, false, "Off")
+	testArgs(t, true, "Yes")
 	testArgs(t, int64(69420), "69420")
 	testArgs(t, uint64(1337), "1337")
 	testArgs(t, 69.420, "69.420")
+	testArgs(t, 90*time.Minute, "1h30m")
+	testArgs(t, discord.Snowflake(69420), "69420")
+	testArgs(t, discord.Snowflake(69420), "<@!69420>")
+	testArgs(t, discord.Snowflake(69420), "<#69420>")
 	testArgs(t, mockParse("testString"), "testString")
+	testArgs(t, discord.Emoji{Name: "👍"}, "👍")
+	testArgs(t, discord.Emoji{Name: "🇯🇵"}, "🇯🇵")
+	testArgs(t, discord.Emoji{Name: "1️⃣"}, "1️⃣")
+	testArgs(t, discord.Emoji{Name: "👨‍👩‍👧"}, "👨‍👩‍👧")
+	testArgs(t, discord.Emoji{ID: 1, Name: "pog"}, "<:pog:1>")
+	testArgs(t, &discord.Emoji{ID: 1, Name: "pog", Animated: true}, "<a:pog:1>")
 	testArgs(t, *mockParse("testString"), "testString")
 
+	testArgsError(t, int64(0), "sixty-nine", "expected integer")
+	testArgsError(t, int8(0), "420", "integer out of range")
+	testArgsError(t, uint64(0), "-1", "expected unsigned integer")
+	testArgsError(t, 0.0, "nice", "expected number")
+	testArgsError(t, time.Duration(0), "soon", `expected duration such as 1h30m, got "soon"`)
+	testArgsError(t, discord.Snowflake(0), "<@nope>", `expected id or mention, got "<@nope>"`)
+	testArgsError(t, false, "maybe", `expected yes or no, got "maybe"`)
+	testArgsError(t, discord.Emoji{}, "pog", `expected emoji, got "pog"`)
+	testArgsError(t, discord.Emoji{}, "<pog:1>", `invalid custom emoji "<pog:1>"`)
+	testArgsError(t, discord.Emoji{}, "<:pog:x>", `invalid custom emoji ID in "<:pog:x>"`)
+
+	a, _ := newArgument(reflect.TypeOf(false), false)
+	if a.String != "[yes|no]" {
+		t.Fatal("Unexpected bool usage:", a.String)
+	}
+
+	a, _ = newArgument(reflect.TypeOf(discord.Emoji{}), false)
+	if a.String != "emoji" {
+		t.Fatal("Unexpected emoji usage:", a.String)
+	}
+
 	_, err := newArgument(reflect.TypeOf(struct{}{}), false)
 	if !strings.HasPrefix(err.Error(), "invalid type: ") {
 		t.Fatal("Unexpected error:", err)
 	}
 }
 
+func TestArgumentLookup(t *testing.T) {
+	var ctx = &Context{
+		State: &state.State{
+			Store: state.NewDefaultStore(nil),
+		},
+	}
+
+	ctx.Store.ChannelSet(&discord.Channel{ID: 1, Name: "general"})
+	ctx.Store.MemberSet(2, &discord.Member{User: discord.User{ID: 3, Username: "hime"}})
+
+	var mc = &gateway.MessageCreateEvent{
+		Message: discord.Message{GuildID: 2},
+	}
+
+	lookup := func(v interface{}, input string) (interface{}, error) {
+		a, err := newArgument(reflect.TypeOf(v), false)
+		if err != nil {
+			t.Fatal("Failed to get argument value function:", err)
+		}
+
+		rv, err := ctx.parseArgument(mc, a, input)
+		if err != nil {
+			return nil, err
+		}
+
+		return rv.Interface(), nil
+	}
+
+	c, err := lookup((*discord.Channel)(nil), "<#1>")
+	if err != nil || c.(*discord.Channel).Name != "general" {
+		t.Fatal("Failed to look up channel:", c, err)
+	}
+
+	u, err := lookup((*discord.User)(nil), "<@!3>")
+	if err != nil || u.(*discord.User).Username != "hime" {
+		t.Fatal("Failed to look up user:", u, err)
+	}
+
+	if _, err := lookup((*discord.User)(nil), "<#3>"); err == nil {
+		t.Fatal("Expected error for a channel mention as a user")
+	}
+}
+
+type taggedArgs struct {
+	Channel discord.Snowflake `arg:"channel"`
+	Count   int               `arg:"count"`
+	Verbose bool              `arg:"verbose,optional"`
+	Ignored string
+}
+
+func TestTaggedArguments(t *testing.T) {
+	a, err := newArgument(reflect.TypeOf(&taggedArgs{}), false)
+	if err != nil {
+		t.Fatal("Failed to get argument:", err)
+	}
+
+	if a.String != "channel=id count=int [--verbose]" {
+		t.Fatal("Unexpected usage:", a.String)
+	}
+
+	var ctx = &Context{}
+	var mc = &gateway.MessageCreateEvent{}
+
+	parse := func(args ...string) (*taggedArgs, error) {
+		var v taggedArgs
+		return &v, ctx.parseTagged(mc, a.tagged, reflect.ValueOf(&v).Elem(), args)
+	}
+
+	v, err := parse("count=50", "channel=<#69420>", "--verbose")
+	if err != nil {
+		t.Fatal("Failed to parse:", err)
+	}
+
+	if *v != (taggedArgs{Channel: 69420, Count: 50, Verbose: true}) {
+		t.Fatal("Unexpected value:", v)
+	}
+
+	if _, err := parse("channel=1"); err == nil || err.Error() != `missing argument "count"` {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	if _, err := parse("--count"); err == nil || err.Error() != `argument "count" expects a value` {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	if _, err := parse("size=1"); err == nil || err.Error() != `unknown argument "size"` {
+		t.Fatal("Unexpected error:", err)
+	}
+}
+
+type flagArgs struct {
+	Count   int  `flag:"count,short=n"`
+	Verbose bool `flag:"verbose,short=v"`
+	Offset  int  `flag:""`
+	Users   Arguments
+}
+
+func TestFlagArguments(t *testing.T) {
+	a, err := newArgument(reflect.TypeOf(&flagArgs{}), false)
+	if err != nil {
+		t.Fatal("Failed to get argument:", err)
+	}
+
+	if a.String != "[-n|--count int] [-v|--verbose] [--offset int] [users...]" {
+		t.Fatal("Unexpected usage:", a.String)
+	}
+
+	var ctx = &Context{}
+	var mc = &gateway.MessageCreateEvent{}
+
+	parse := func(args ...string) (*flagArgs, error) {
+		var v flagArgs
+		return &v, ctx.parseFlags(mc, a.flags, reflect.ValueOf(&v).Elem(), args)
+	}
+
+	var tests = []struct {
+		args   []string
+		expect flagArgs
+	}{
+		{nil, flagArgs{}},
+		{[]string{"a", "-n", "50", "b", "--verbose"}, flagArgs{Count: 50, Verbose: true, Users: Arguments{"a", "b"}}},
+		{[]string{"--count=5", "-v=false", "--offset", "-1", "-2"}, flagArgs{Count: 5, Offset: -1, Users: Arguments{"-2"}}},
+		{[]string{"-v", "--", "-n", "1"}, flagArgs{Verbose: true, Users: Arguments{"-n", "1"}}},
+	}
+
+	for _, test := range tests {
+		v, err := parse(test.args...)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", test.args, err)
+		}
+		if !reflect.DeepEqual(*v, test.expect) {
+			t.Fatalf("Unexpected value for %q: %+v", test.args, *v)
+		}
+	}
+
+	var errs = []struct {
+		args   []string
+		expect string
+	}{
+		{[]string{"--size", "1"}, `unknown flag "size"`},
+		{[]string{"-n"}, `flag "n" expects a value`},
+		{[]string{"-n", "many"}, `flag "n": expected integer`},
+	}
+
+	for _, test := range errs {
+		if _, err := parse(test.args...); err == nil || err.Error() != test.expect {
+			t.Errorf("Unexpected error for %q: %v", test.args, err)
+		}
+	}
+
+	// Positional arguments need a field to go into.
+	type noArgs struct {
+		Verbose bool `flag:"verbose"`
+	}
+
+	a, err = newArgument(reflect.TypeOf(&noArgs{}), false)
+	if err != nil {
+		t.Fatal("Failed to get argument:", err)
+	}
+	if err := ctx.parseFlags(mc, a.flags, reflect.ValueOf(&noArgs{}).Elem(), []string{"a"}); err == nil {
+		t.Fatal("Expected error for a positional argument")
+	}
+
+	type mixed struct {
+		Verbose bool `flag:"verbose"`
+		Count   int  `arg:"count"`
+	}
+
+	if _, err := newArgument(reflect.TypeOf(&mixed{}), false); err == nil {
+		t.Fatal("Expected error for mixed flags and arguments")
+	}
+}
+
 func testArgs(t *testing.T, expect interface{}, input string) {
 	f, err := newArgument(reflect.TypeOf(expect), false)
 	if err != nil {
@@ -49,6 +261,17 @@ func testArgs(t *testing.T, expect interface{}, input string) {
 	}
 }
 
+func testArgsError(t *testing.T, typ interface{}, input, expect string) {
+	f, err := newArgument(reflect.TypeOf(typ), false)
+	if err != nil {
+		t.Fatal("Failed to get argument value function:", err)
+	}
+
+	if _, err := f.fn(input); err == nil || err.Error() != expect {
+		t.Fatal("Unexpected error:", err, "\nExpects:", expect)
+	}
+}
+
 // used for ctx_test.go
 
 type customManualParsed struct {
@@ -68,3 +291,50 @@ func (c *customParsed) Parse(string) error {
 	c.parsed = true
 	return nil
 }
+
+type customUsageParsed struct {
+	customManualParsed
+}
+
+func (c *customUsageParsed) Usage() string {
+	return "from to [message]"
+}
+
+func TestManualParserUsage(t *testing.T) {
+	a, err := newArgument(reflect.TypeOf(&customUsageParsed{}), false)
+	if err != nil {
+		t.Fatal("Failed to create argument:", err)
+	}
+	if a.String != "from to [message]" || !a.full {
+		t.Fatal("Unexpected usage:", a.String)
+	}
+
+	var cmd = CommandContext{
+		Command:   "move",
+		Variadic:  true,
+		Arguments: []Argument{*a},
+	}
+	if help := (&Subcommand{}).helpCommand("", &cmd); strings.Contains(help, "...") {
+		t.Fatal("Unexpected ellipsis in help:", help)
+	}
+
+	// Manual parsers without Usager still get the ellipsis.
+	a, _ = newArgument(reflect.TypeOf(&customManualParsed{}), false)
+	if a.full {
+		t.Fatal("Unexpected full usage:", a.String)
+	}
+}
+
+func TestSetDefaultsParser(t *testing.T) {
+	for _, v := range []interface{}{new(RawArguments), &customManualParsed{}, &taggedArgs{}, &flagArgs{}} {
+		a, err := newArgument(reflect.TypeOf(v), false)
+		if err != nil {
+			t.Fatal("Failed to create argument:", err)
+		}
+
+		var cmd = CommandContext{Arguments: []Argument{*a}}
+		if err := cmd.SetDefaults("a"); err == nil {
+			t.Fatalf("Expected error for a default of %T", v)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package bot
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/diamondburned/arikawa/api"
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/pkg/errors"
+)
+
+var typeInteraction = reflect.TypeOf((*gateway.InteractionCreateEvent)(nil))
+
+// ComponentHandler handles the interactions of a message component, such as
+// a button being clicked. The returned response, if not nil, is sent with
+// RespondInteraction. Otherwise, the handler has to respond on its own.
+type ComponentHandler func(ev *gateway.InteractionCreateEvent) (*api.InteractionResponse, error)
+
+// AddComponentHandler adds the handler for component interactions with the
+// custom ID, replacing the previous one, if any. If no handler matches the
+// whole custom ID of an interaction, the part before its first colon is
+// tried, so the custom ID can carry data after it:
+//
+//    // Handles the custom IDs "vote:1", "vote:2" and so on.
+//    ctx.AddComponentHandler("vote", func(ev *gateway.InteractionCreateEvent) (*api.InteractionResponse, error) {
+//        option := strings.TrimPrefix(ev.Data.CustomID, "vote:")
+//        return &api.InteractionResponse{
+//            Type: api.MessageResponse,
+//            Data: &api.InteractionResponseData{
+//                Content: "You voted for " + option,
+//                Flags:   discord.EphemeralMessage,
+//            },
+//        }, nil
+//    })
+//
+// Like commands, panics are recovered and errors are given to ErrorLogger. The
+// returned function removes the handler.
+func (ctx *Context) AddComponentHandler(customID string, fn ComponentHandler) (rm func()) {
+	var cmd = &CommandContext{
+		MethodName: customID,
+		value:      reflect.ValueOf(fn),
+		event:      typeInteraction,
+	}
+
+	ctx.componentMutex.Lock()
+	defer ctx.componentMutex.Unlock()
+
+	if ctx.components == nil {
+		ctx.components = map[string]*CommandContext{}
+	}
+	ctx.components[customID] = cmd
+
+	return func() {
+		ctx.componentMutex.Lock()
+		defer ctx.componentMutex.Unlock()
+
+		// Don't remove a handler that replaced this one.
+		if ctx.components[customID] == cmd {
+			delete(ctx.components, customID)
+		}
+	}
+}
+
+// findComponent returns the handler for the custom ID, or nil if there's none.
+func (ctx *Context) findComponent(customID string) *CommandContext {
+	ctx.componentMutex.RLock()
+	defer ctx.componentMutex.RUnlock()
+
+	if cmd, ok := ctx.components[customID]; ok {
+		return cmd
+	}
+
+	if i := strings.IndexByte(customID, ':'); i > -1 {
+		return ctx.components[customID[:i]]
+	}
+
+	return nil
+}
+
+// callComponent calls the handler of the component interaction and sends its
+// response. Interactions without a handler are ignored.
+func (ctx *Context) callComponent(ev *gateway.InteractionCreateEvent) error {
+	if ev.Type != discord.ComponentInteraction {
+		return nil
+	}
+
+	cmd := ctx.findComponent(ev.Data.CustomID)
+	if cmd == nil {
+		return nil
+	}
+
+	v, err := ctx.callCommand(cmd, ev)
+	if err != nil {
+		return err
+	}
+
+	resp, _ := v.(*api.InteractionResponse)
+	if resp == nil {
+		return nil
+	}
+
+	if err := ctx.RespondInteraction(ev.ID, ev.Token, *resp); err != nil {
+		return errors.Wrap(err, "Failed to respond to interaction")
+	}
+
+	return nil
+}
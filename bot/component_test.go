@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/diamondburned/arikawa/api"
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/diamondburned/arikawa/state"
+)
+
+func TestComponentHandler(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	var got []string
+	handler := func(name string) ComponentHandler {
+		return func(ev *gateway.InteractionCreateEvent) (*api.InteractionResponse, error) {
+			got = append(got, name+" "+ev.Data.CustomID)
+			return nil, nil
+		}
+	}
+
+	click := func(customID string) error {
+		return c.callCmd(&gateway.InteractionCreateEvent{
+			Type: discord.ComponentInteraction,
+			Data: discord.InteractionData{CustomID: customID},
+		})
+	}
+
+	rmVote := c.AddComponentHandler("vote", handler("vote"))
+	c.AddComponentHandler("vote:2", handler("exact"))
+	c.AddComponentHandler("fail", func(*gateway.InteractionCreateEvent) (*api.InteractionResponse, error) {
+		return nil, errors.New("failed")
+	})
+
+	for _, id := range []string{"vote:1", "vote:2", "vote", "unknown", "unknown:1"} {
+		if err := click(id); err != nil {
+			t.Fatal("Unexpected error:", err)
+		}
+	}
+
+	const expects = "[vote vote:1 exact vote:2 vote vote]"
+	if s := fmt.Sprint(got); s != expects {
+		t.Fatal("Unexpected calls:", s)
+	}
+
+	if err := click("fail"); err == nil || err.Error() != "failed" {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	// Other interaction types aren't for components.
+	got = nil
+	c.callCmd(&gateway.InteractionCreateEvent{
+		Type: discord.CommandInteraction,
+		Data: discord.InteractionData{CustomID: "vote"},
+	})
+
+	rmVote()
+	click("vote:1")
+
+	if len(got) != 0 {
+		t.Fatal("Unexpected calls:", got)
+	}
+}
@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+)
+
+// CooldownScope determines what a command's cooldown is keyed on.
+type CooldownScope uint8
+
+const (
+	// PerUser makes each user have their own cooldown. This is the default.
+	PerUser CooldownScope = iota
+	// PerChannel makes everyone in the same channel share a cooldown.
+	PerChannel
+	// PerGuild makes everyone in the same guild share a cooldown. Direct
+	// messages fall back to PerChannel.
+	PerGuild
+)
+
+// Cooldown limits how often a command can be invoked. A cooldown can be set
+// during Setup:
+//
+//    func (c *Commands) Setup(sub *bot.Subcommand) {
+//        sub.FindCommand("Expensive").Cooldown = bot.NewCooldown(
+//            10*time.Second, bot.PerUser,
+//        )
+//    }
+//
+type Cooldown struct {
+	Duration time.Duration
+	Scope    CooldownScope
+
+	mutex  sync.Mutex
+	last   map[discord.Snowflake]time.Time
+	lastGC time.Time
+}
+
+// NewCooldown creates a new cooldown with the given duration and scope.
+func NewCooldown(d time.Duration, scope CooldownScope) *Cooldown {
+	return &Cooldown{
+		Duration: d,
+		Scope:    scope,
+		last:     map[discord.Snowflake]time.Time{},
+	}
+}
+
+// Use marks the cooldown as used by the author of the given message. If the
+// cooldown hasn't expired yet, the remaining duration is returned and the
+// cooldown is left untouched. Otherwise, 0 is returned.
+func (c *Cooldown) Use(mc *gateway.MessageCreateEvent) time.Duration {
+	var key = c.key(mc)
+	var now = time.Now()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.last == nil {
+		c.last = map[discord.Snowflake]time.Time{}
+	}
+
+	// Sweep all expired entries once every duration, so bots that run for a
+	// long time don't keep every single user around forever.
+	if now.Sub(c.lastGC) > c.Duration {
+		for k, t := range c.last {
+			if now.Sub(t) >= c.Duration {
+				delete(c.last, k)
+			}
+		}
+		c.lastGC = now
+	}
+
+	if t, ok := c.last[key]; ok {
+		if remaining := c.Duration - now.Sub(t); remaining > 0 {
+			return remaining
+		}
+	}
+
+	c.last[key] = now
+	return 0
+}
+
+// Reset resets the cooldown for everyone.
+func (c *Cooldown) Reset() {
+	c.mutex.Lock()
+	c.last = map[discord.Snowflake]time.Time{}
+	c.mutex.Unlock()
+}
+
+func (c *Cooldown) key(mc *gateway.MessageCreateEvent) discord.Snowflake {
+	switch c.Scope {
+	case PerGuild:
+		if mc.GuildID.Valid() {
+			return mc.GuildID
+		}
+		fallthrough
+	case PerChannel:
+		return mc.ChannelID
+	default:
+		return mc.Author.ID
+	}
+}
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+)
+
+func TestCooldown(t *testing.T) {
+	newMessage := func(guildID, channelID, userID discord.Snowflake) *gateway.MessageCreateEvent {
+		return &gateway.MessageCreateEvent{
+			Message: discord.Message{
+				GuildID:   guildID,
+				ChannelID: channelID,
+				Author:    discord.User{ID: userID},
+			},
+		}
+	}
+
+	t.Run("per user", func(t *testing.T) {
+		c := NewCooldown(time.Minute, PerUser)
+
+		if d := c.Use(newMessage(1, 2, 3)); d != 0 {
+			t.Fatal("Unexpected cooldown on first use:", d)
+		}
+		if d := c.Use(newMessage(1, 5, 3)); d <= 0 {
+			t.Fatal("Expected cooldown for the same user")
+		}
+		if d := c.Use(newMessage(1, 2, 4)); d != 0 {
+			t.Fatal("Unexpected cooldown for another user:", d)
+		}
+	})
+
+	t.Run("per guild", func(t *testing.T) {
+		c := NewCooldown(time.Minute, PerGuild)
+
+		if d := c.Use(newMessage(1, 2, 3)); d != 0 {
+			t.Fatal("Unexpected cooldown on first use:", d)
+		}
+		if d := c.Use(newMessage(1, 5, 4)); d <= 0 {
+			t.Fatal("Expected cooldown for the same guild")
+		}
+		// Direct messages are keyed by channel instead.
+		if d := c.Use(newMessage(0, 6, 3)); d != 0 {
+			t.Fatal("Unexpected cooldown in a direct message:", d)
+		}
+	})
+
+	t.Run("expire", func(t *testing.T) {
+		c := NewCooldown(time.Millisecond, PerChannel)
+		c.Use(newMessage(0, 1, 1))
+
+		time.Sleep(2 * time.Millisecond)
+
+		if d := c.Use(newMessage(0, 2, 1)); d != 0 {
+			t.Fatal("Unexpected cooldown:", d)
+		}
+		if len(c.last) != 1 {
+			t.Fatal("Expired entries were not collected:", len(c.last))
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := &ErrOnCooldown{Remaining: 1500 * time.Millisecond}
+		if s := err.Error(); s != "Please wait 2 seconds before using this command again." {
+			t.Fatal("Unexpected error string:", s)
+		}
+	})
+}
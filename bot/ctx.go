@@ -1,24 +1,52 @@
 package bot
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
 
+	"github.com/diamondburned/arikawa/api"
+	"github.com/diamondburned/arikawa/discord"
 	"github.com/diamondburned/arikawa/gateway"
 	"github.com/diamondburned/arikawa/state"
+	"github.com/diamondburned/arikawa/utils/wsutil"
 	"github.com/pkg/errors"
 )
 
 // Prefixer checks a message if it starts with the desired prefix. By default,
 // NewPrefix() is used.
+//
+// Since Prefixer is a function, it can also look up the prefix dynamically per
+// message, such as from a per-guild setting. Returning ok as false makes the
+// message be ignored. Example:
+//
+//    ctx.HasPrefix = func(m *gateway.MessageCreateEvent) (string, bool) {
+//        prefix := db.GuildPrefix(m.GuildID)
+//        return prefix, strings.HasPrefix(m.Content, prefix)
+//    }
+//
+// The returned prefix is trimmed from the message content before parsing.
 type Prefixer func(*gateway.MessageCreateEvent) (prefix string, ok bool)
 
 // NewPrefix creates a simple prefix checker using strings. As the default
-// prefix is "!", the function is called as NewPrefix("!").
+// prefix is "!", the function is called as NewPrefix("!"). Several prefixes
+// could be given, such as NewPrefix("!", "?"). The longest matching prefix is
+// used, so NewPrefix("!", "!!") trims "!!" from "!!help".
 func NewPrefix(prefixes ...string) Prefixer {
+	prefixes = append([]string(nil), prefixes...)
+	sort.SliceStable(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+
 	return func(msg *gateway.MessageCreateEvent) (string, bool) {
 		for _, prefix := range prefixes {
 			if strings.HasPrefix(msg.Content, prefix) {
@@ -29,6 +57,32 @@ func NewPrefix(prefixes ...string) Prefixer {
 	}
 }
 
+// NewSelfPrefix creates a prefix checker that matches messages starting with a
+// mention of the bot itself, in either the <@id> or the <@!id> form. Whitespaces
+// after the mention are considered part of the prefix. The bot's user is looked
+// up from the given state.
+func NewSelfPrefix(s *state.State) Prefixer {
+	return func(msg *gateway.MessageCreateEvent) (string, bool) {
+		me, err := s.Me()
+		if err != nil {
+			return "", false
+		}
+
+		var id = me.ID.String()
+
+		for _, mention := range [...]string{"<@" + id + ">", "<@!" + id + ">"} {
+			if !strings.HasPrefix(msg.Content, mention) {
+				continue
+			}
+
+			rest := strings.TrimLeftFunc(msg.Content[len(mention):], unicode.IsSpace)
+			return msg.Content[:len(msg.Content)-len(rest)], true
+		}
+
+		return "", false
+	}
+}
+
 // TODO: add variadic arguments
 
 // Context is the bot state for commands and subcommands.
@@ -50,9 +104,13 @@ func NewPrefix(prefixes ...string) Prefixer {
 // capitalizes the first letter automatically to reflect the exported method
 // name.
 //
-// A command can either return either an error, or data and error. The only data
-// types allowed are string, *discord.Embed, and *api.SendMessageData. Any other
-// return types will invalidate the method.
+// A command can either return either an error, or data and error. The data
+// types that are replied are string, *discord.Embed, []*discord.Embed,
+// *api.SendMessageData, and *FileReply. Any other return types will invalidate
+// the method, unless the Subcommand's ReplyJSON is true. Strings longer than
+// MessageMax are split into several messages with SplitMessage. A command can
+// also return a string and an *discord.Embed before the error, which are sent
+// in one message.
 //
 // Events
 //
@@ -73,14 +131,34 @@ type Context struct {
 	// Descriptive help body
 	Description string
 
-	// Called to check a message's prefix. The default prefix is "!". Refer to
-	// NewPrefix().
+	// Called to check a message's prefix. The default prefix is "~". Refer to
+	// NewPrefix() and Prefixer.
 	HasPrefix Prefixer
 
+	// Dispatch decides whether commands and events are handled concurrently,
+	// one at a time, or one at a time for each channel. Refer to DispatchMode.
+	//
+	// The State's Handler calls each handler in its own goroutine by default,
+	// so events may already be out of order by the time they reach the
+	// Context. Set the Handler's Synchronous to true if the order matters; the
+	// Context then queues the events without blocking the Handler.
+	Dispatch DispatchMode
+
 	// AllowBot makes the router also process MessageCreate events from bots.
 	// This is false by default and only applies to MessageCreate.
 	AllowBot bool
 
+	// AllowedMentions is used for all replies that don't set their own,
+	// including error replies. New sets this to parse no mentions at all, so
+	// commands that echo the user's input can't ping anyone. Set this to nil
+	// to let Discord parse all mentions.
+	AllowedMentions *api.AllowedMentions
+
+	// AllowSelf makes the router also process MessageCreate events from the
+	// bot itself, which could cause loops. This is false by default, and
+	// unlike AllowBot, it also applies when the bot is a user account.
+	AllowSelf bool
+
 	// FormatError formats any errors returned by anything, including the method
 	// commands or the reflect functions. This also includes invalid usage
 	// errors or unknown command errors. Returning an empty string means
@@ -90,6 +168,33 @@ type Context struct {
 	// @everyone mention.
 	FormatError func(error) string
 
+	// SuggestDistance is the maximum edit distance between an unknown command
+	// and a known command, subcommand or alias for the latter to be suggested.
+	// A value of 0 disables suggestions. New sets this to 2.
+	SuggestDistance int
+
+	// FormatUnknownCommand, if not nil, formats unknown command errors instead
+	// of FormatError. Returning an empty string means ignoring the error. Refer
+	// to QuietUnknownCommand to disable the reply entirely.
+	FormatUnknownCommand func(*ErrUnknownCommand) string
+
+	// ErrorReply, if not nil, makes the reply to a command's error instead of
+	// FormatError and FormatUnknownCommand when ReplyError is true. It could
+	// use errors.As to reply differently to known errors. Returning false
+	// gives the error to ErrorLogger instead of replying, which hides
+	// internal errors from the users. The reply's content is sanitized, and
+	// AllowedMentions is used if the reply has none.
+	//
+	//    ctx.ErrorReply = func(err error) (*api.SendMessageData, bool) {
+	//        var perms *bot.ErrMissingPermissions
+	//        if errors.As(err, &perms) {
+	//            return &api.SendMessageData{Content: "Ask a moderator."}, true
+	//        }
+	//        return nil, false
+	//    }
+	//
+	ErrorReply func(err error) (*api.SendMessageData, bool)
+
 	// ErrorLogger logs any error that anything makes and the library can't
 	// reply to the client. This includes any event callback errors that aren't
 	// Message Create.
@@ -99,13 +204,70 @@ type Context struct {
 	// MessageCreate events.
 	ReplyError bool
 
-	// Subcommands contains all the registered subcommands. This is not
-	// exported, as it shouldn't be used directly.
-	subcommands []*Subcommand
+	// OnPanic, if not nil, is called with the recovered value when a command or
+	// handler panics. The panic is then turned into an ErrPanic, which goes
+	// through the usual error path. Since OnPanic is called before the stack
+	// unwinds, debug.Stack() can be used to get the stack trace. New sets this
+	// to log the panic along with the stack trace.
+	OnPanic func(interface{})
+
+	// OnPermissionDenied, if not nil, is called when a non-admin invokes an
+	// AdminOnly command, which is otherwise ignored silently. It could be used
+	// to log the attempt or reply with a custom message.
+	OnPermissionDenied func(m *gateway.MessageCreateEvent, cmd *CommandContext)
+
+	// CommandEnabled, if not nil, is called before a command is ran in a guild
+	// to check whether the guild has it enabled, which could be looked up from
+	// a database to let admins turn commands off. This applies to commands in
+	// subcommands too. Disabled commands return an ErrCommandDisabled. It's
+	// not called outside of guilds.
+	CommandEnabled func(guildID discord.Snowflake, cmd *CommandContext) bool
+
+	// MaxInteractions is the maximum number of interactive sessions, such as
+	// Confirm, NextMessage and Paginate, that can wait for the users at once.
+	// Starting more returns ErrTooManyInteractions, so abandoned menus can't
+	// pile up. A value of 0 means no limit. New sets this to 1000.
+	MaxInteractions int
+
+	// OnCommandStart, if not nil, is called before a matched command is ran,
+	// with the arguments that are yet to be parsed.
+	OnCommandStart func(cmd *CommandContext, m *gateway.MessageCreateEvent, args []string)
+
+	// OnCommandEnd, if not nil, is called after a command started with
+	// OnCommandStart is done. The given error is the one that the command
+	// returned, if any, including argument and reply errors.
+	OnCommandEnd func(
+		cmd *CommandContext, m *gateway.MessageCreateEvent, took time.Duration, err error)
 
 	// Quick access map from event types to pointers. This map will never have
-	// MessageCreateEvent's type.
-	typeCache sync.Map // map[reflect.Type][]*CommandContext
+	// MessageCreateEvent's type. It's reset whenever commands or subcommands
+	// are added or removed.
+	typeCache map[reflect.Type][]*CommandContext
+	typeMutex sync.RWMutex
+
+	// dispatch queues the events if Dispatch isn't DispatchConcurrent.
+	dispatch dispatcher
+
+	// simulations maps the events given to SimulateMessage to their recorded
+	// replies.
+	simulations sync.Map // map[*gateway.MessageCreateEvent]*[]api.SendMessageData
+
+	// components maps custom IDs to their handlers. Refer to
+	// AddComponentHandler.
+	components     map[string]*CommandContext
+	componentMutex sync.RWMutex
+
+	// interactions is the number of active interactive sessions.
+	interactions     int
+	interactionMutex sync.Mutex
+
+	// stopCtx is given to methods that take a context.Context. It is cancelled
+	// when the function returned by Start is called.
+	stopCtx    context.Context
+	stopCancel context.CancelFunc
+
+	shutdownMutex    sync.Mutex
+	shutdownHandlers []func() error
 }
 
 // Start quickly starts a bot with the given command. It will prepend "Bot"
@@ -144,13 +306,21 @@ func Start(token string, cmd interface{},
 		// remove handler first
 		cancel()
 		// then finish closing session
-		return s.Close()
+		var errs []error
+		if err := s.Close(); err != nil {
+			errs = append(errs, errors.Wrap(err, "Failed to close session"))
+		}
+		// and run the shutdown handlers last
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		return joinErrors(errs)
 	}, nil
 }
 
 // Wait blocks until SIGINT.
 func Wait() {
-	sigs := make(chan os.Signal)
+	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, os.Interrupt)
 	<-sigs
 }
@@ -181,14 +351,24 @@ func New(s *state.State, cmd interface{}) (*Context, error) {
 		HasPrefix:  NewPrefix("~"),
 		FormatError: func(err error) string {
 			// Escape all pings, including @everyone.
-			return strings.Replace(err.Error(), "@", "@\u200b", -1)
+			return EscapeMentions(err.Error())
 		},
 		ErrorLogger: func(err error) {
 			log.Println("Bot error:", err)
 		},
-		ReplyError: true,
+		OnPanic: func(v interface{}) {
+			log.Printf("Bot panic: %v\n%s", v, debug.Stack())
+		},
+		ReplyError:      true,
+		SuggestDistance: 2,
+		MaxInteractions: 1000,
+		AllowedMentions: &api.AllowedMentions{
+			Parse: []api.AllowedMentionType{},
+		},
 	}
 
+	ctx.stopCtx, ctx.stopCancel = context.WithCancel(context.Background())
+
 	if err := ctx.InitCommands(ctx); err != nil {
 		return nil, errors.Wrap(err, "Failed to initialize with given cmds")
 	}
@@ -196,15 +376,54 @@ func New(s *state.State, cmd interface{}) (*Context, error) {
 	return ctx, nil
 }
 
-// Subcommands returns the slice of subcommands. To add subcommands, use
-// RegisterSubcommand().
-func (ctx *Context) Subcommands() []*Subcommand {
-	// Getter is not useless, refer to the struct doc for reason.
-	return ctx.subcommands
+// CommandInfo describes a registered command. It's a copy, so changing it
+// doesn't change the command.
+type CommandInfo struct {
+	// Subcommand is the name of the subcommand that the command belongs to,
+	// or empty for the main commands. The names of nested subcommands include
+	// their parents' names, such as "config server".
+	Subcommand  string
+	Command     string
+	Description string
+	Aliases     []string
+	// Usage is the usage of each argument, the same as CommandContext's.
+	Usage       []string
+	Variadic    bool
+	Flag        NameFlag
+	Permissions discord.Permissions
+}
+
+// AllCommands returns all commands of the main context and its subcommands,
+// including nested and hidden ones, in the same order as the help. This could be
+// used for custom help messages or dashboards.
+func (ctx *Context) AllCommands() []CommandInfo {
+	var infos []CommandInfo
+
+	add := func(sub string, cmds []*CommandContext) {
+		for _, cmd := range cmds {
+			infos = append(infos, CommandInfo{
+				Subcommand:  sub,
+				Command:     cmd.Command,
+				Description: cmd.Description,
+				Aliases:     append([]string(nil), cmd.Aliases...),
+				Usage:       cmd.Usage(),
+				Variadic:    cmd.Variadic,
+				Flag:        cmd.Flag,
+				Permissions: cmd.Permissions,
+			})
+		}
+	}
+
+	add("", ctx.Commands)
+	for _, sub := range ctx.allSubcommands() {
+		add(sub.fullCommand(), sub.Commands)
+	}
+
+	return infos
 }
 
 // FindCommand finds a command based on the struct and method name. The queried
-// names will have their flags stripped.
+// names will have their flags stripped. Nested subcommands are searched too.
 //
 // Example
 //
@@ -224,7 +443,7 @@ func (ctx *Context) FindCommand(structname, methodname string) *CommandContext {
 		return nil
 	}
 
-	for _, sub := range ctx.subcommands {
+	for _, sub := range ctx.allSubcommands() {
 		if sub.StructName != structname {
 			continue
 		}
@@ -239,91 +458,325 @@ func (ctx *Context) FindCommand(structname, methodname string) *CommandContext {
 	return nil
 }
 
-// MustRegisterSubcommand tries to register a subcommand, and will panic if it
-// fails. This is recommended, as subcommands won't change after initializing
-// once in runtime, thus fairly harmless after development.
-func (ctx *Context) MustRegisterSubcommand(cmd interface{}) *Subcommand {
-	s, err := ctx.RegisterSubcommand(cmd)
-	if err != nil {
-		panic(err)
+// Start adds itself into the discordgo Session handlers. This needs to be run.
+// The returned function is a delete function, which removes itself from the
+// Session handlers and cancels the context given to running commands.
+func (ctx *Context) Start() func() {
+	rm := ctx.State.AddHandler(func(v interface{}) {
+		switch ctx.Dispatch {
+		case DispatchSynchronous:
+			ctx.dispatch.run(0, func() { ctx.handle(v) })
+		case DispatchPerChannel:
+			if id := reflectChannelID(v); id.Valid() {
+				ctx.dispatch.run(id, func() { ctx.handle(v) })
+			} else {
+				ctx.handle(v)
+			}
+		default:
+			ctx.handle(v)
+		}
+	})
+
+	return func() {
+		rm()
+
+		if ctx.stopCancel != nil {
+			ctx.stopCancel()
+		}
 	}
+}
 
-	return s
+// handle calls the commands and event handlers for the event, then replies
+// with or logs the error, if any.
+func (ctx *Context) handle(v interface{}) {
+	ctx.handleError(v, ctx.callCmd(v))
 }
 
-// RegisterSubcommand registers and adds cmd to the list of subcommands. It will
-// also return the resulting Subcommand.
-func (ctx *Context) RegisterSubcommand(cmd interface{}) (*Subcommand, error) {
-	s, err := NewSubcommand(cmd)
-	if err != nil {
-		return nil, errors.Wrap(err, "Failed to add subcommand")
+// handleError replies with or logs the error that handling the event returned,
+// if any.
+func (ctx *Context) handleError(v interface{}, err error) {
+	if err == nil {
+		return
+	}
+
+	mc, isMessage := v.(*gateway.MessageCreateEvent)
+
+	if ctx.ErrorReply != nil && ctx.ReplyError && isMessage {
+		data, ok := ctx.ErrorReply(err)
+		if !ok || data == nil {
+			ctx.logError(err)
+			return
+		}
+
+		ctx.replyError(mc, *data)
+		return
 	}
 
-	// Register the subcommand's name.
-	s.NeedsName()
+	str := ctx.formatError(err)
+	if str == "" {
+		return
+	}
 
-	if err := s.InitCommands(ctx); err != nil {
-		return nil, errors.Wrap(err, "Failed to initialize subcommand")
+	// Log the main error if reply is disabled or if the event isn't a
+	// message.
+	if !ctx.ReplyError || !isMessage {
+		ctx.logError(err)
+		return
 	}
 
-	// Do a collision check
-	for _, sub := range ctx.subcommands {
-		if sub.Command == s.Command {
-			return nil, errors.New(
-				"New subcommand has duplicate name: " + s.Command)
-		}
+	ctx.replyError(mc, api.SendMessageData{Content: str})
+}
+
+// logError gives the error to ErrorLogger, unless it's a trivial error caused
+// by the user.
+func (ctx *Context) logError(err error) {
+	switch err.(type) {
+	case *ErrInvalidUsage, *ErrUnknownCommand, *ErrOnCooldown,
+		*ErrGuildOnly, *ErrDMOnly, *ErrMissingPermissions, *ErrCommandDisabled:
+		// Ignore
+	default:
+		ctx.ErrorLogger(errors.Wrap(err, "Command error"))
+	}
+}
+
+// replyError sends the error reply to the message's channel.
+func (ctx *Context) replyError(mc *gateway.MessageCreateEvent, data api.SendMessageData) {
+	// Escape the error using the message sanitizer:
+	if data.Content != "" {
+		data.Content = ctx.SanitizeMessage(data.Content)
+	}
+	if data.AllowedMentions == nil {
+		data.AllowedMentions = ctx.AllowedMentions
 	}
 
-	ctx.subcommands = append(ctx.subcommands, s)
-	return s, nil
+	if _, err := ctx.sendMessage(mc, data); err != nil {
+		ctx.ErrorLogger(err)
+	}
 }
 
-// Start adds itself into the discordgo Session handlers. This needs to be run.
-// The returned function is a delete function, which removes itself from the
-// Session handlers.
-func (ctx *Context) Start() func() {
-	return ctx.State.AddHandler(func(v interface{}) {
-		err := ctx.callCmd(v)
-		if err == nil {
-			return
+// OnShutdown adds a function to be called when the Context is closed, either
+// through Close or when the session created by Start is closed. Functions are
+// called in the reverse order that they were added.
+func (ctx *Context) OnShutdown(fn func() error) {
+	ctx.shutdownMutex.Lock()
+	ctx.shutdownHandlers = append(ctx.shutdownHandlers, fn)
+	ctx.shutdownMutex.Unlock()
+}
+
+// Close cancels the context given to running commands and calls all shutdown
+// handlers added with OnShutdown. Each handler is only ever called once. All
+// errors returned by the handlers are combined into a ShutdownErrors.
+//
+// Close does not remove the event handler added by Start nor close the
+// session.
+func (ctx *Context) Close() error {
+	if ctx.stopCancel != nil {
+		ctx.stopCancel()
+	}
+
+	ctx.shutdownMutex.Lock()
+	handlers := ctx.shutdownHandlers
+	ctx.shutdownHandlers = nil
+	ctx.shutdownMutex.Unlock()
+
+	var errs []error
+
+	for i := len(handlers) - 1; i >= 0; i-- {
+		if err := handlers[i](); err != nil {
+			errs = append(errs, err)
 		}
+	}
 
-		str := ctx.FormatError(err)
-		if str == "" {
-			return
+	return joinErrors(errs)
+}
+
+// AddHandler adds an event handler that isn't a method of any struct. It
+// accepts the same signatures as an event method, meaning the function may take
+// a context.Context first and may return an error, which is given to
+// ErrorLogger. The handler is called for every event of its argument's type:
+//
+//    ctx.AddHandler(func(ev *gateway.TypingStartEvent) error {
+//        return nil
+//    })
+//
+// A channel of an event type may be given instead of a function, which is
+// added to the State's Handler as it is. Refer to the handler package for how
+// events are sent into channels.
+//
+// The returned function removes the handler. AddHandler panics if fn has an
+// invalid signature.
+func (ctx *Context) AddHandler(fn interface{}) (rm func()) {
+	rm, err := ctx.AddHandlerCheck(fn)
+	if err != nil {
+		panic(err)
+	}
+	return rm
+}
+
+// AddHandlerCheck is like AddHandler, but it returns an error instead of
+// panicking.
+func (ctx *Context) AddHandlerCheck(fn interface{}) (rm func(), err error) {
+	var fnV = reflect.ValueOf(fn)
+	var fnT = fnV.Type()
+
+	if fnT.Kind() == reflect.Chan {
+		return ctx.State.AddHandlerCheck(fn)
+	}
+
+	if fnT.Kind() != reflect.Func {
+		return nil, errors.New("Handler is not a function")
+	}
+
+	var cmd = CommandContext{
+		value: fnV,
+	}
+
+	switch numIn := fnT.NumIn(); {
+	case numIn == 1:
+		cmd.event = fnT.In(0)
+	case numIn == 2 && fnT.In(0) == typeIContext:
+		cmd.event = fnT.In(1)
+		cmd.withContext = true
+	default:
+		return nil, errors.New("Handler must only take an event")
+	}
+
+	switch numOut := fnT.NumOut(); {
+	case numOut > 2:
+		return nil, errors.New("Handler returns too many values")
+	case numOut > 0 && !fnT.Out(numOut-1).Implements(typeIError):
+		return nil, errors.New("Handler's last return is not an error")
+	}
+
+	// Wrap the handler in a function without returns, which is what the State
+	// handler accepts.
+	var wrapT = reflect.FuncOf([]reflect.Type{cmd.event}, nil, false)
+	var wrap = reflect.MakeFunc(wrapT, func(args []reflect.Value) []reflect.Value {
+		if _, err := ctx.callCommand(&cmd, args[0]); err != nil {
+			if err = onlyFatal(err); err != nil {
+				ctx.ErrorLogger(err)
+			}
 		}
+		return nil
+	})
 
-		mc, isMessage := v.(*gateway.MessageCreateEvent)
-
-		// Log the main error if reply is disabled or if the event isn't a
-		// message.
-		if !ctx.ReplyError || !isMessage {
-			// Ignore trivial errors:
-			switch err.(type) {
-			case *ErrInvalidUsage, *ErrUnknownCommand:
-				// Ignore
-			default:
-				ctx.ErrorLogger(errors.Wrap(err, "Command error"))
+	return ctx.State.AddHandlerCheck(wrap.Interface())
+}
+
+// DeriveIntents returns the Gateway intents needed to receive the events that
+// the commands and event handlers of the Context and its subcommands take.
+// Handlers added with AddHandler aren't counted. The message content intent
+// is never included, as the current Gateway version doesn't need it.
+//
+//    ctx.Gateway.AddIntent(ctx.DeriveIntents())
+//
+func (ctx *Context) DeriveIntents() gateway.Intents {
+	var intents gateway.Intents
+	var add = func(evT reflect.Type) {
+		for name, fn := range gateway.EventCreator {
+			if reflect.TypeOf(fn()) == evT {
+				intents = intents.Add(gateway.EventIntents[name])
 			}
+		}
+	}
 
-			return
+	var subs = append([]*Subcommand{ctx.Subcommand}, ctx.allSubcommands()...)
+
+	for _, sub := range subs {
+		if len(sub.Commands) > 0 {
+			add(typeMessageCreate)
+		}
+		for _, cmd := range sub.Events {
+			add(cmd.event)
 		}
+		for _, cmd := range sub.mwMethods {
+			add(cmd.event)
+		}
+	}
 
-		// Only reply if the event is not a message.
-		if !isMessage {
-			return
+	return intents
+}
+
+// SetGame sets the bot's status to online and playing the game with the given
+// name, or to just online if the name is empty. For other statuses or activity
+// types, use UpdateStatus. The status is kept across reconnections.
+//
+//    ctx.SetGame("~help")
+//
+func (ctx *Context) SetGame(name string) error {
+	var data = gateway.UpdateStatusData{
+		Status: discord.OnlineStatus,
+	}
+
+	if name != "" {
+		data.Game = &discord.Activity{
+			Name: name,
+			Type: discord.GameActivity,
 		}
+	}
 
-		// Escape the error using the message sanitizer:
-		str = ctx.SanitizeMessage(str)
+	return ctx.UpdateStatus(data)
+}
 
-		_, err = ctx.SendMessage(mc.ChannelID, str, nil)
-		if err != nil {
-			ctx.ErrorLogger(err)
+// RotateStatus sets the game to each of the statuses in turn, moving to the
+// next one every interval, until the returned function is called or the
+// Context is closed. The "{guilds}" in a status is replaced with the State's
+// GuildCount. Refer to SetGame. Nothing is done if there are no statuses or if
+// the interval isn't positive.
+//
+//    ctx.RotateStatus([]string{"~help", "in {guilds} servers"}, 5*time.Minute)
+//
+func (ctx *Context) RotateStatus(statuses []string, interval time.Duration) (stop func()) {
+	if len(statuses) == 0 || interval <= 0 {
+		return func() {}
+	}
+
+	var stopped = ctx.stopContext().Done()
+
+	var done = make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		var tick = time.NewTicker(interval)
+		defer tick.Stop()
 
-			// TODO: there ought to be a better way lol
+		for i := 0; ; i = (i + 1) % len(statuses) {
+			ctx.setStatus(statuses[i])
+
+			select {
+			case <-done:
+				return
+			case <-stopped:
+				return
+			case <-tick.C:
+			}
 		}
-	})
+	}()
+
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// setStatus fills in the status for RotateStatus and sets it as the game.
+func (ctx *Context) setStatus(status string) {
+	if strings.Contains(status, "{guilds}") {
+		status = strings.Replace(status, "{guilds}", strconv.Itoa(ctx.GuildCount()), -1)
+	}
+
+	// The Gateway isn't opened yet, but it still identifies with the status.
+	err := ctx.SetGame(status)
+	if err != nil && !errors.Is(err, wsutil.ErrWebsocketClosed) {
+		ctx.ErrorLogger(errors.Wrap(err, "Failed to rotate status"))
+	}
+}
+
+// formatError formats the error with FormatUnknownCommand if it's an unknown
+// command error and the function is set, or FormatError otherwise.
+func (ctx *Context) formatError(err error) string {
+	if unknown, ok := err.(*ErrUnknownCommand); ok && ctx.FormatUnknownCommand != nil {
+		return ctx.FormatUnknownCommand(unknown)
+	}
+
+	return ctx.FormatError(err)
 }
 
 // Call should only be used if you know what you're doing.
@@ -366,8 +819,9 @@ func (ctx *Context) help(hideAdmin bool) string {
 	help.WriteString("\n---\n")
 
 	// Generate all commands
-	help.WriteString("__Commands__")
-	help.WriteString(ctx.Subcommand.Help(indent, hideAdmin))
+	// Only the main commands, as the subcommands are listed below.
+	help.WriteString("__Commands__\n")
+	help.WriteString(strings.Join(ctx.Subcommand.helpLines(indent, hideAdmin), "\n"))
 	help.WriteByte('\n')
 
 	var subHelp = strings.Builder{}
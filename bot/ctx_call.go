@@ -1,12 +1,15 @@
 package bot
 
 import (
+	"context"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/diamondburned/arikawa/api"
 	"github.com/diamondburned/arikawa/discord"
 	"github.com/diamondburned/arikawa/gateway"
+	"github.com/diamondburned/arikawa/utils/json"
 	"github.com/pkg/errors"
 )
 
@@ -72,7 +75,7 @@ func (ctx *Context) filterEventType(evT reflect.Type) []*CommandContext {
 	// Find the main context first.
 	find(ctx.Subcommand)
 
-	for _, sub := range ctx.subcommands {
+	for _, sub := range ctx.allSubcommands() {
 		// Reset found status
 		found = false
 		// Find subcommands second.
@@ -82,6 +85,44 @@ func (ctx *Context) filterEventType(evT reflect.Type) []*CommandContext {
 	return append(middles, callers...)
 }
 
+// eventCallers returns the cached callers of the event type, filling the cache
+// if needed.
+func (ctx *Context) eventCallers(evT reflect.Type) []*CommandContext {
+	ctx.typeMutex.RLock()
+	callers, ok := ctx.typeCache[evT]
+	ctx.typeMutex.RUnlock()
+
+	if ok {
+		return callers
+	}
+
+	// The callers are found under the lock, so a reset can't be overwritten
+	// by callers found before it.
+	ctx.typeMutex.Lock()
+	defer ctx.typeMutex.Unlock()
+
+	if callers, ok := ctx.typeCache[evT]; ok {
+		return callers
+	}
+
+	if ctx.typeCache == nil {
+		ctx.typeCache = map[reflect.Type][]*CommandContext{}
+	}
+
+	callers = ctx.filterEventType(evT)
+	ctx.typeCache[evT] = callers
+
+	return callers
+}
+
+// resetTypeCache clears the cached callers, so newly added or removed commands
+// and subcommands are taken into account.
+func (ctx *Context) resetTypeCache() {
+	ctx.typeMutex.Lock()
+	ctx.typeCache = nil
+	ctx.typeMutex.Unlock()
+}
+
 func (ctx *Context) callCmd(ev interface{}) error {
 	evT := reflect.TypeOf(ev)
 
@@ -90,30 +131,25 @@ func (ctx *Context) callCmd(ev interface{}) error {
 	var callers []*CommandContext
 
 	// Hit the cache
-	t, ok := ctx.typeCache.Load(evT)
-	if ok {
-		callers = t.([]*CommandContext)
-	} else {
-		callers = ctx.filterEventType(evT)
-		ctx.typeCache.Store(evT, callers)
-	}
+	callers = ctx.eventCallers(evT)
 
 	// We can't do the callers[:0] trick here, as it will modify the slice
-	// inside the sync.Map as well.
+	// inside the cache as well.
 	var filtered = make([]*CommandContext, 0, len(callers))
 
 	for _, cmd := range callers {
 		// Command flags will inherit its parent Subcommand's flags.
 		if true &&
 			!(cmd.Flag.Is(AdminOnly) && !ctx.eventIsAdmin(ev, &isAdmin)) &&
-			!(cmd.Flag.Is(GuildOnly) && !ctx.eventIsGuild(ev, &isGuild)) {
+			!(cmd.Flag.Is(GuildOnly) && !ctx.eventIsGuild(ev, &isGuild)) &&
+			!(cmd.Flag.Is(DMOnly) && !ctx.eventIsDM(ev, &isGuild)) {
 
 			filtered = append(filtered, cmd)
 		}
 	}
 
 	for _, c := range filtered {
-		_, err := callWith(c.value, ev)
+		_, err := ctx.callCommand(c, ev)
 		if err != nil {
 			if err = onlyFatal(err); err != nil {
 				ctx.ErrorLogger(err)
@@ -122,6 +158,11 @@ func (ctx *Context) callCmd(ev interface{}) error {
 		}
 	}
 
+	if evT == typeInteraction {
+		err := ctx.callComponent(ev.(*gateway.InteractionCreateEvent))
+		return onlyFatal(err)
+	}
+
 	// We call the messages later, since Hidden handlers will go into the Events
 	// slice, but we don't want to ignore those handlers either.
 	if evT == typeMessageCreate {
@@ -133,12 +174,19 @@ func (ctx *Context) callCmd(ev interface{}) error {
 	return nil
 }
 
-func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) error {
+func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) (err error) {
 	// check if bot
 	if !ctx.AllowBot && mc.Author.Bot {
 		return nil
 	}
 
+	// check if self, only from the store, so the API isn't hit every message
+	if !ctx.AllowSelf {
+		if me, err := ctx.Store.Me(); err == nil && me.ID == mc.Author.ID {
+			return nil
+		}
+	}
+
 	// check if prefix
 	pf, ok := ctx.HasPrefix(mc)
 	if !ok {
@@ -152,10 +200,11 @@ func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) error {
 		return nil // just the prefix only
 	}
 
-	// parse arguments
-	parts, err := ParseArgs(content)
-	if err != nil {
-		return errors.Wrap(err, "Failed to parse command")
+	// parse arguments, but the error only matters if the command doesn't parse
+	// the raw content, which could have unbalanced quotes like in code
+	parts, parseErr := ParseArgs(content)
+	if parseErr != nil {
+		parts = strings.Fields(content)
 	}
 
 	if len(parts) == 0 {
@@ -179,7 +228,7 @@ func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) error {
 	// If not plumb, search for the command
 	if cmd == nil {
 		for _, c := range ctx.Commands {
-			if c.Command == parts[0] {
+			if c.isCommand(parts[0]) {
 				cmd = c
 				sub = ctx.Subcommand
 				arguments = arguments[1:]
@@ -189,77 +238,142 @@ func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) error {
 		}
 	}
 
-	// Can't find the command, look for subcommands if len(args) has a 2nd
-	// entry.
+	// Can't find the command, look for subcommands. Nested subcommands are
+	// walked down for as long as the words match their names.
 	if cmd == nil {
-		for _, s := range ctx.subcommands {
-			if s.Command != parts[0] {
-				continue
+		var parent = ctx.Subcommand
+		var depth int // the number of words naming subcommands
+
+		for s := ctx.Subcommand; depth < len(parts); depth++ {
+			next := findSubcommand(s.Subcommands(), parts[depth])
+			if next == nil {
+				break
 			}
 
-			// Check if plumb:
-			if s.plumb {
-				cmd = s.Commands[0]
-				sub = s
-				arguments = arguments[1:]
-				// start = 1
+			parent, sub, s = s, next, next
+		}
+
+		switch {
+		case depth == 0:
+			// Not a subcommand.
+
+		// Check if plumb:
+		case sub.plumb:
+			cmd = sub.Commands[0]
+			arguments = arguments[depth:]
+
+		// There's no word left, so we can only look for Plumbed subcommands.
+		case depth == len(parts):
+			// The main commands' unknown command error is used below.
+			if parent == ctx.Subcommand {
 				break
 			}
 
-			// There's no second argument, so we can only look for Plumbed
-			// subcommands.
-			if len(parts) < 2 {
-				continue
+			if sub.QuietUnknownCommand {
+				return nil
 			}
 
-			for _, c := range s.Commands {
-				if c.Command == parts[1] {
+			// The name is right, so there's nothing to suggest.
+			return &ErrUnknownCommand{
+				Prefix:  pf,
+				Command: parts[depth-1],
+				Parent:  strings.Join(parts[:depth-1], " "),
+				ctx:     sub.Commands,
+			}
+
+		default:
+			for _, c := range sub.Commands {
+				if c.isCommand(parts[depth]) {
 					cmd = c
-					sub = s
-					arguments = arguments[2:]
+					arguments = arguments[depth+1:]
 					break
-					// start = 2
 				}
 			}
 
 			if cmd == nil {
-				if s.QuietUnknownCommand {
+				if sub.QuietUnknownCommand {
 					return nil
 				}
 
 				return &ErrUnknownCommand{
-					Command: parts[1],
-					Parent:  parts[0],
-					ctx:     s.Commands,
+					Prefix:     pf,
+					Command:    parts[depth],
+					Parent:     strings.Join(parts[:depth], " "),
+					Suggestion: ctx.suggest(parts[depth], subcommandNames(sub)),
+					ctx:        sub.Commands,
 				}
 			}
-
-			break
 		}
 	}
 
+	if parseErr != nil && (cmd == nil || !cmd.takesRaw()) {
+		return errors.Wrap(parseErr, "Failed to parse command")
+	}
+
 	if cmd == nil {
 		if ctx.QuietUnknownCommand {
 			return nil
 		}
 
 		return &ErrUnknownCommand{
-			Command: parts[0],
-			ctx:     ctx.Commands,
+			Prefix:     pf,
+			Command:    parts[0],
+			Suggestion: ctx.suggest(parts[0], subcommandNames(ctx.Subcommand)),
+			ctx:        ctx.Commands,
 		}
 	}
 
 	// Check for IsAdmin and IsGuild
 	if cmd.Flag.Is(GuildOnly) && !mc.GuildID.Valid() {
-		return nil
+		return &ErrGuildOnly{Ctx: cmd}
+	}
+	if cmd.Flag.Is(DMOnly) && mc.GuildID.Valid() {
+		return &ErrDMOnly{Ctx: cmd}
+	}
+	if ctx.CommandEnabled != nil && mc.GuildID.Valid() && !ctx.CommandEnabled(mc.GuildID, cmd) {
+		return &ErrCommandDisabled{Ctx: cmd}
 	}
 	if cmd.Flag.Is(AdminOnly) {
 		p, err := ctx.State.Permissions(mc.ChannelID, mc.Author.ID)
 		if err != nil || !p.Has(discord.PermissionAdministrator) {
+			if ctx.OnPermissionDenied != nil {
+				ctx.OnPermissionDenied(mc, cmd)
+			}
 			return nil
 		}
 	}
 
+	// The number of words naming the command, including subcommands.
+	var cmdWords = len(parts) - len(arguments)
+
+	// Split the arguments again if the command has its own delimiter. The
+	// command words are kept in parts, so error indices still line up.
+	if cmd.Delimiter != "" {
+		arguments = splitDelimiter(skipWords(content, cmdWords), cmd.Delimiter)
+		parts = append(parts[:cmdWords:cmdWords], arguments...)
+	}
+
+	if ctx.OnCommandStart != nil {
+		ctx.OnCommandStart(cmd, mc, arguments)
+	}
+	if ctx.OnCommandEnd != nil {
+		start := time.Now()
+		defer func() { ctx.OnCommandEnd(cmd, mc, time.Since(start), err) }()
+	}
+
+	if cmd.Permissions != 0 && mc.GuildID.Valid() {
+		p, err := ctx.State.Permissions(mc.ChannelID, mc.Author.ID)
+		if err != nil {
+			return errors.Wrap(err, "Failed to get permissions")
+		}
+		if !p.Has(cmd.Permissions) {
+			return &ErrMissingPermissions{
+				Missing: cmd.Permissions &^ p,
+				Ctx:     cmd,
+			}
+		}
+	}
+
 	// Start converting
 	var argv []reflect.Value
 	var argc int
@@ -267,6 +381,9 @@ func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) error {
 	// the last argument in the list, not used until set
 	var last Argument
 
+	// The arguments before any are parsed, in case the method wants them.
+	var raw = Arguments(arguments)
+
 	// Here's an edge case: when the handler takes no arguments, we allow that
 	// anyway, as they might've used the raw content.
 	if len(cmd.Arguments) < 1 {
@@ -274,18 +391,19 @@ func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) error {
 	}
 
 	// Argument count check.
-	if argdelta := len(arguments) - len(cmd.Arguments); argdelta != 0 {
+	if argdelta := len(arguments) - len(cmd.Arguments); argdelta != 0 || cmd.bounded() {
 		var err error // no err if nil
 
 		// If the function is variadic, then we can allow the last argument to
-		// be empty.
+		// be empty. argdelta is then the number of variadic arguments.
 		if cmd.Variadic {
 			argdelta++
 		}
 
 		switch {
-		// If there aren't enough arguments given.
-		case argdelta < 0:
+		// If there aren't enough arguments given. Optional arguments are always
+		// trailing, so only the first omitted one needs to be checked.
+		case argdelta < 0 && !cmd.Arguments[len(arguments)].Optional:
 			err = ErrNotEnoughArgs
 
 		// If there are too many arguments, then check if the command supports
@@ -293,6 +411,12 @@ func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) error {
 		case argdelta > 0 && !cmd.Variadic:
 			// If it's not variadic, then we can't accept it.
 			err = ErrTooManyArgs
+
+		// If the variadic arguments are out of bounds.
+		case cmd.Variadic && argdelta < cmd.variadicMin:
+			err = ErrNotEnoughArgs
+		case cmd.variadicMax > 0 && argdelta > cmd.variadicMax:
+			err = ErrTooManyArgs
 		}
 
 		if err != nil {
@@ -300,8 +424,13 @@ func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) error {
 				Prefix: pf,
 				Args:   parts,
 				Index:  len(parts) - 1,
-				Wrap:   err,
-				Ctx:    cmd,
+				Wrap: &ErrArgumentCount{
+					Command: ctx.commandName(sub, cmd),
+					Got:     len(arguments),
+					Wrap:    err,
+					Ctx:     cmd,
+				},
+				Ctx: cmd,
 			}
 		}
 	}
@@ -315,13 +444,24 @@ func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) error {
 
 	// Parse all arguments except for the last one.
 	for i := 0; i < argc; i++ {
-		v, err := cmd.Arguments[i].fn(arguments[0])
+		// Use the default value if the argument is omitted.
+		if len(arguments) == 0 {
+			v, err := ctx.defaultArgument(mc, cmd, i)
+			if err != nil {
+				return err
+			}
+
+			argv = append(argv, v)
+			continue
+		}
+
+		v, err := ctx.parseArgument(mc, &cmd.Arguments[i], arguments[0])
 		if err != nil {
 			return &ErrInvalidUsage{
 				Prefix: pf,
 				Args:   parts,
-				Index:  len(parts) - len(arguments) + i,
-				Wrap:   err,
+				Index:  len(parts) - len(arguments),
+				Wrap:   argumentError(i, cmd.Arguments[i], err),
 				Ctx:    cmd,
 			}
 		}
@@ -333,20 +473,29 @@ func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) error {
 
 	// Is this last argument actually a variadic slice? If yes, then it
 	// should still have fn normally.
-	if last.fn != nil {
+	if last.fn != nil && last.Optional && len(arguments) == 0 {
+		// Use the default value if the last argument is omitted.
+		v, err := ctx.defaultArgument(mc, cmd, argc)
+		if err != nil {
+			return err
+		}
+
+		argv = append(argv, v)
+
+	} else if last.fn != nil {
 		// Allocate a new slice to append into.
 		vars := make([]reflect.Value, 0, len(arguments))
 
 		// Parse the rest with variadic arguments. Go's reflect states that
 		// varidic parameters will automatically be copied, which is good.
 		for i := 0; len(arguments) > 0; i++ {
-			v, err := last.fn(arguments[0])
+			v, err := ctx.parseArgument(mc, &last, arguments[0])
 			if err != nil {
 				return &ErrInvalidUsage{
 					Prefix: pf,
 					Args:   parts,
-					Index:  len(parts) - len(arguments) + i,
-					Wrap:   err,
+					Index:  len(parts) - len(arguments),
+					Wrap:   argumentError(argc+i, last, err),
 					Ctx:    cmd,
 				}
 			}
@@ -363,31 +512,50 @@ func (ctx *Context) callMessageCreate(mc *gateway.MessageCreateEvent) error {
 		var err error // return error
 
 		switch {
+		// If the argument is a struct with tagged fields:
+		case last.tagged != nil:
+			if err := ctx.parseTagged(mc, last.tagged, v.Elem(), arguments); err != nil {
+				return &ErrInvalidUsage{
+					Wrap: err,
+					Ctx:  cmd,
+				}
+			}
+
+		// If the argument is a struct with flag fields:
+		case last.flags != nil:
+			if err := ctx.parseFlags(mc, last.flags, v.Elem(), arguments); err != nil {
+				return &ErrInvalidUsage{
+					Wrap: err,
+					Ctx:  cmd,
+				}
+			}
+
 		// If the argument wants all arguments:
 		case last.manual != nil:
 			// Call the manual parse method:
 			_, err = callWith(last.manual.Func, v, reflect.ValueOf(arguments))
 
+		// If the argument wants the content as it was sent:
+		case last.rtype == typeContent:
+			// Skip the command only.
+			var rest = rawContent(content, cmdWords)
+			_, err = callWith(last.custom.Func, v, reflect.ValueOf(rest))
+
+		// If the argument wants the rest of the arguments in string:
+		case last.rtype == typeRemainder:
+			// Skip the command and all parsed arguments.
+			var rest = skipWords(content, len(parts)-len(arguments))
+			_, err = callWith(last.custom.Func, v, reflect.ValueOf(rest))
+
 		// If the argument wants all arguments in string:
 		case last.custom != nil:
 			// Manual string seeking is a must here. This is because the string
 			// could contain multiple whitespaces, and the parser would not
-			// count them.
-			var seekTo = cmd.Command
-			// If plumbed, then there would only be the subcommand.
-			if sub.plumb {
-				seekTo = sub.Command
-			}
-
-			// Seek to the string.
-			if i := strings.Index(content, seekTo); i > -1 {
-				// Seek past the substring.
-				i += len(seekTo)
-				content = strings.TrimSpace(content[i:])
-			}
+			// count them. Only the command words are skipped.
+			var rest = skipWords(content, cmdWords)
 
 			// Call the method with the raw unparsed command:
-			_, err = callWith(last.custom.Func, v, reflect.ValueOf(content))
+			_, err = callWith(last.custom.Func, v, reflect.ValueOf(rest))
 		}
 
 		// Check the returned error:
@@ -408,32 +576,223 @@ Call:
 	// Try calling all middlewares first. We don't need to stack middlewares, as
 	// there will only be one command match.
 	for _, mw := range sub.mwMethods {
-		_, err := callWith(mw.value, mc)
+		// Skip middlewares that are meant for other events.
+		if !typeMessageCreate.AssignableTo(mw.event) {
+			continue
+		}
+
+		_, err := ctx.callCommand(mw, mc)
 		if err != nil {
 			return err
 		}
 	}
 
+	// Check the cooldown last, so invalid usages don't count.
+	if cmd.Cooldown != nil {
+		if remaining := cmd.Cooldown.Use(mc); remaining > 0 {
+			return &ErrOnCooldown{
+				Remaining: remaining,
+				Ctx:       cmd,
+			}
+		}
+	}
+
+	if (cmd.ShowTyping || sub.ShowTyping) && !ctx.isSimulated(mc) {
+		stop := ctx.startTyping(mc.ChannelID)
+		defer stop()
+	}
+
+	if cmd.withArguments {
+		argv = append([]reflect.Value{reflect.ValueOf(raw)}, argv...)
+	}
+
 	// call the function and parse the error return value
-	v, err := callWith(cmd.value, mc, argv...)
+	v, err := ctx.callCommand(cmd, mc, argv...)
 	if err != nil {
 		return err
 	}
 
+	var ref *discord.MessageReference
+	if ctx.ReplyToUser || sub.ReplyToUser {
+		ref = &discord.MessageReference{
+			ChannelID: mc.ChannelID,
+			MessageID: mc.ID,
+			GuildID:   mc.GuildID,
+		}
+	}
+
+	// The messages sent as the reply, for DeleteReplyAfter.
+	var sent []*discord.Message
+
 	switch v := v.(type) {
 	case string:
-		v = sub.SanitizeMessage(v)
-		_, err = ctx.SendMessage(mc.ChannelID, v, nil)
+		sent, err = ctx.sendContent(mc, api.SendMessageData{
+			Content:         sub.SanitizeMessage(v),
+			AllowedMentions: ctx.AllowedMentions,
+			Reference:       ref,
+		})
 	case *discord.Embed:
-		_, err = ctx.SendMessage(mc.ChannelID, "", v)
+		sent, err = ctx.sendOne(mc, api.SendMessageData{
+			Embed:           v,
+			AllowedMentions: ctx.AllowedMentions,
+			Reference:       ref,
+		})
+	case []*discord.Embed:
+		// Embeds have no content, so there's nothing to sanitize.
+		var data = api.SendMessageData{
+			Embeds:          make([]discord.Embed, 0, len(v)),
+			AllowedMentions: ctx.AllowedMentions,
+			Reference:       ref,
+		}
+		for _, embed := range v {
+			if embed != nil {
+				data.Embeds = append(data.Embeds, *embed)
+			}
+		}
+		sent, err = ctx.sendOne(mc, data)
 	case *api.SendMessageData:
 		if v.Content != "" {
 			v.Content = sub.SanitizeMessage(v.Content)
 		}
-		_, err = ctx.SendMessageComplex(mc.ChannelID, *v)
+		if v.AllowedMentions == nil {
+			v.AllowedMentions = ctx.AllowedMentions
+		}
+		if v.Reference == nil {
+			v.Reference = ref
+		}
+		sent, err = ctx.sendOne(mc, *v)
+	case *FileReply:
+		var data = api.SendMessageData{
+			Content:         v.Content,
+			Embed:           v.Embed,
+			Files:           v.Files,
+			AllowedMentions: ctx.AllowedMentions,
+			Reference:       ref,
+		}
+		if data.Content != "" {
+			data.Content = sub.SanitizeMessage(data.Content)
+		}
+		sent, err = ctx.sendOne(mc, data)
+	default:
+		if v != nil && sub.ReplyJSON {
+			b, jsonErr := json.MarshalIndent(v, "", "  ")
+			if jsonErr != nil {
+				return errors.Wrap(jsonErr, "Failed to marshal reply")
+			}
+
+			var content = sub.SanitizeMessage("```json\n" + string(b) + "\n```")
+			sent, err = ctx.sendContent(mc, api.SendMessageData{
+				Content:         content,
+				AllowedMentions: ctx.AllowedMentions,
+				Reference:       ref,
+			})
+		}
 	}
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	ctx.cleanUp(cmd, mc, sent)
+	return nil
+}
+
+// sendMessage sends the data to the message's channel, or records it if the
+// message is simulated. The data is checked against Discord's limits first, so
+// the error is clearer than the one from the API.
+func (ctx *Context) sendMessage(
+	mc *gateway.MessageCreateEvent, data api.SendMessageData) (*discord.Message, error) {
+
+	if err := data.Validate(); err != nil {
+		return nil, errors.Wrap(err, "Invalid reply")
+	}
+
+	v, ok := ctx.simulations.Load(mc)
+	if !ok {
+		return ctx.SendMessageComplex(mc.ChannelID, data)
+	}
+
+	replies := v.(*[]api.SendMessageData)
+	*replies = append(*replies, data)
+
+	return &discord.Message{
+		ChannelID: mc.ChannelID,
+		GuildID:   mc.GuildID,
+		Content:   data.Content,
+	}, nil
+}
+
+// sendOne sends a single message, returning it in a slice like sendContent.
+func (ctx *Context) sendOne(
+	mc *gateway.MessageCreateEvent, data api.SendMessageData) ([]*discord.Message, error) {
+
+	m, err := ctx.sendMessage(mc, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*discord.Message{m}, nil
+}
+
+// cleanUp schedules the deletion of the reply and the invoking message, as set
+// with DeleteReplyAfter and DeleteInvocation.
+func (ctx *Context) cleanUp(
+	cmd *CommandContext, mc *gateway.MessageCreateEvent, sent []*discord.Message) {
+
+	if ctx.isSimulated(mc) {
+		return
+	}
+
+	var ids []discord.Snowflake
+
+	if cmd.DeleteReplyAfter > 0 {
+		for _, m := range sent {
+			ids = append(ids, m.ID)
+		}
+	}
+
+	// Bulk deleting also needs this permission, which bots never have in DMs.
+	var canManage = ctx.canManageMessages(mc.GuildID, mc.ChannelID)
+
+	if cmd.DeleteInvocation && canManage {
+		ids = append(ids, mc.ID)
+	}
+
+	if len(ids) == 0 {
+		return
+	}
+
+	time.AfterFunc(cmd.DeleteReplyAfter, func() {
+		if canManage && len(ids) > 1 {
+			if _, err := ctx.DeleteMessages(mc.ChannelID, ids); err != nil {
+				ctx.ErrorLogger(errors.Wrap(err, "Failed to clean up command"))
+			}
+			return
+		}
+
+		// Only the bot's own messages are left, which it can always delete.
+		for _, id := range ids {
+			if err := ctx.DeleteMessage(mc.ChannelID, id); err != nil {
+				ctx.ErrorLogger(errors.Wrap(err, "Failed to clean up command"))
+			}
+		}
+	})
+}
+
+// canManageMessages returns whether the bot can delete others' messages in the
+// channel. It's always false outside of guilds.
+func (ctx *Context) canManageMessages(guildID, channelID discord.Snowflake) bool {
+	if !guildID.Valid() {
+		return false
+	}
+
+	me, err := ctx.State.Me()
+	if err != nil {
+		return false
+	}
+
+	p, err := ctx.State.Permissions(channelID, me.ID)
+	return err == nil && p.Has(discord.PermissionManageMessages)
 }
 
 func (ctx *Context) eventIsAdmin(ev interface{}, is **bool) bool {
@@ -482,6 +841,128 @@ func (ctx *Context) eventIsGuild(ev interface{}, is **bool) bool {
 	return res
 }
 
+// eventIsDM returns true if the event is in a channel outside of guilds. is is
+// shared with eventIsGuild.
+func (ctx *Context) eventIsDM(ev interface{}, is **bool) bool {
+	if !reflectChannelID(ev).Valid() {
+		return false
+	}
+
+	return !ctx.eventIsGuild(ev, is)
+}
+
+// suggest returns the closest name to the unknown command, or an empty string
+// if suggestions are disabled or there's no close name.
+func (ctx *Context) suggest(command string, names []string) string {
+	if ctx.SuggestDistance <= 0 {
+		return ""
+	}
+	return suggestCommand(command, ctx.SuggestDistance, names)
+}
+
+// TypingInterval is the interval between each typing indicator trigger for
+// commands with ShowTyping. Discord clears the indicator after 10 seconds.
+var TypingInterval = 8 * time.Second
+
+// startTyping triggers the typing indicator in the given channel and keeps it
+// refreshed until the returned function is called.
+func (ctx *Context) startTyping(channelID discord.Snowflake) (stop func()) {
+	var done = make(chan struct{})
+
+	go func() {
+		var tick = time.NewTicker(TypingInterval)
+		defer tick.Stop()
+
+		for {
+			if err := ctx.Typing(channelID); err != nil {
+				ctx.ErrorLogger(errors.Wrap(err, "Failed to trigger typing"))
+				return
+			}
+
+			select {
+			case <-done:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// parseArgument parses the input into the argument's value. If the argument
+// refers to an entity, such as a user or a channel, then it is also looked up.
+func (ctx *Context) parseArgument(
+	mc *gateway.MessageCreateEvent, arg *Argument, input string) (reflect.Value, error) {
+
+	v, err := arg.fn(input)
+	if err != nil || arg.lookup == nil {
+		return v, err
+	}
+
+	return arg.lookup(ctx, mc, v.Interface().(discord.Snowflake))
+}
+
+// defaultArgument parses the default value of the command's i-th argument.
+func (ctx *Context) defaultArgument(
+	mc *gateway.MessageCreateEvent, cmd *CommandContext, i int) (reflect.Value, error) {
+
+	v, err := ctx.parseArgument(mc, &cmd.Arguments[i], cmd.Arguments[i].def)
+	if err != nil {
+		return nilV, &ErrInvalidUsage{
+			Wrap: argumentError(i, cmd.Arguments[i], err),
+			Ctx:  cmd,
+		}
+	}
+
+	return v, nil
+}
+
+// argumentError prefixes the argument's position (starting from 1) and its
+// usage to the error returned by the argument's parser.
+func argumentError(i int, arg Argument, err error) error {
+	return errors.Wrapf(err, "argument %d (%s)", i+1, arg.String)
+}
+
+// stopContext returns the context that's cancelled once the Context is
+// stopped, or the background context if the Context wasn't made with New.
+func (ctx *Context) stopContext() context.Context {
+	if ctx.stopCtx == nil {
+		return context.Background()
+	}
+	return ctx.stopCtx
+}
+
+// callCommand calls the command's method with the event and the given values.
+// The Context's context is prepended if the method wants one.
+func (ctx *Context) callCommand(
+	cmd *CommandContext,
+	ev interface{}, values ...reflect.Value) (_ interface{}, err error) {
+
+	defer func() {
+		if v := recover(); v != nil {
+			if ctx.OnPanic != nil {
+				ctx.OnPanic(v)
+			}
+			err = &ErrPanic{Value: v, Ctx: cmd}
+		}
+	}()
+
+	if !cmd.withContext {
+		return callWith(cmd.value, ev, values...)
+	}
+
+	var c = ctx.stopContext()
+
+	evV, ok := ev.(reflect.Value)
+	if !ok {
+		evV = reflect.ValueOf(ev)
+	}
+
+	values = append([]reflect.Value{evV}, values...)
+	return callWith(cmd.value, reflect.ValueOf(c), values...)
+}
+
 func callWith(
 	caller reflect.Value,
 	ev interface{}, values ...reflect.Value) (interface{}, error) {
@@ -516,6 +997,18 @@ func errorReturns(returns []reflect.Value) (interface{}, error) {
 			return nil, nil
 		}
 
+		// (string, *discord.Embed, error) is sent as a single message.
+		if len(returns) == 3 {
+			var content = returns[0].String()
+			var embed = returns[1].Interface().(*discord.Embed)
+
+			if content == "" && embed == nil {
+				return nil, nil
+			}
+
+			return &api.SendMessageData{Content: content, Embed: embed}, nil
+		}
+
 		// Return the first argument as-is. The above returns[-1] check assumes
 		// 2 return values (T, error), meaning returns[0] is the T value.
 		return returns[0].Interface(), nil
@@ -589,3 +1082,18 @@ func _reflectID(v reflect.Value, thing string) discord.Snowflake {
 
 	return 0
 }
+
+// commandName returns the full name of the command as typed by the user,
+// without the prefix.
+func (ctx *Context) commandName(sub *Subcommand, cmd *CommandContext) string {
+	var name = sub.fullCommand()
+
+	switch {
+	case sub == ctx.Subcommand || name == "":
+		return cmd.Command
+	case cmd.Command == "":
+		return name
+	default:
+		return name + " " + cmd.Command
+	}
+}
@@ -38,7 +38,7 @@ func TestSubcommandPlumb(t *testing.T) {
 
 	p := &hasPlumb{}
 
-	_, err = c.RegisterSubcommand(p)
+	sub, err := c.RegisterSubcommand(p)
 	if err != nil {
 		t.Fatal("Failed to register hasPlumb:", err)
 	}
@@ -47,6 +47,13 @@ func TestSubcommandPlumb(t *testing.T) {
 		t.Fatal("Unexpected length for sub.Commands:", l)
 	}
 
+	if !sub.IsPlumbed() || sub.PlumbCommand().MethodName != "Plumber" {
+		t.Fatal("Unexpected plumb command:", sub.PlumbCommand())
+	}
+	if c.IsPlumbed() || c.PlumbCommand() != nil {
+		t.Fatal("Main commands are plumbed")
+	}
+
 	// Try call exactly what's in the Plumb example:
 	m := &gateway.MessageCreateEvent{
 		Message: discord.Message{
@@ -1,17 +1,27 @@
 package bot
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
 	"reflect"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/diamondburned/arikawa/api"
 	"github.com/diamondburned/arikawa/discord"
 	"github.com/diamondburned/arikawa/gateway"
+	"github.com/diamondburned/arikawa/handler"
+	"github.com/diamondburned/arikawa/session"
 	"github.com/diamondburned/arikawa/state"
+	"github.com/diamondburned/arikawa/utils/wsutil"
+	"golang.org/x/time/rate"
 )
 
 type testc struct {
@@ -34,6 +44,23 @@ func (t *testc) Send(_ *gateway.MessageCreateEvent, args ...string) error {
 	return errors.New("oh no")
 }
 
+func (t *testc) Sum(_ *gateway.MessageCreateEvent, ns ...int) {
+	var sum int
+	for _, n := range ns {
+		sum += n
+	}
+	t.Return <- sum
+}
+
+func (t *testc) WithContext(ctx context.Context, _ *gateway.MessageCreateEvent, s string) error {
+	t.Return <- s
+	return ctx.Err()
+}
+
+func (t *testc) Paged(_ *gateway.MessageCreateEvent, s string, page int) {
+	t.Return <- page
+}
+
 func (t *testc) Custom(_ *gateway.MessageCreateEvent, c *customManualParsed) {
 	t.Return <- c.args
 }
@@ -46,6 +73,10 @@ func (t *testc) TrailCustom(_ *gateway.MessageCreateEvent, s string, c *customMa
 	t.Return <- c.args
 }
 
+func (t *testc) Remainder(_ *gateway.MessageCreateEvent, s string, r RawRemainder) {
+	t.Return <- r
+}
+
 func (t *testc) Content(_ *gateway.MessageCreateEvent, c RawArguments) {
 	t.Return <- c
 }
@@ -76,6 +107,359 @@ func TestNewContext(t *testing.T) {
 	}
 }
 
+func TestContextClose(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	var order []int
+
+	for i := 0; i < 3; i++ {
+		i := i
+		c.OnShutdown(func() error {
+			order = append(order, i)
+			if i > 0 {
+				return fmt.Errorf("error %d", i)
+			}
+			return nil
+		})
+	}
+
+	err = c.Close()
+	if err == nil || err.Error() != "error 2; error 1" {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	if !reflect.DeepEqual(order, []int{2, 1, 0}) {
+		t.Fatal("Unexpected shutdown order:", order)
+	}
+
+	if c.stopCtx.Err() == nil {
+		t.Fatal("Context was not cancelled")
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal("Unexpected error on second close:", err)
+	}
+}
+
+func TestContextAddHandler(t *testing.T) {
+	var state = &state.State{
+		Store:   state.NewDefaultStore(nil),
+		Handler: handler.New(),
+	}
+	state.Synchronous = true
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	var logged error
+	c.ErrorLogger = func(err error) { logged = err }
+
+	var typed bool
+	rm := c.AddHandler(func(ctx context.Context, ev *gateway.TypingStartEvent) error {
+		typed = true
+		return errors.New("typed")
+	})
+
+	state.Call(&gateway.TypingStartEvent{})
+
+	if !typed {
+		t.Fatal("Handler was not called")
+	}
+	if logged == nil || logged.Error() != "typed" {
+		t.Fatal("Unexpected logged error:", logged)
+	}
+
+	rm()
+	typed = false
+	state.Call(&gateway.TypingStartEvent{})
+
+	if typed {
+		t.Fatal("Handler was called after removal")
+	}
+
+	if _, err := c.AddHandlerCheck(func(string, int) {}); err == nil {
+		t.Fatal("Expected error for invalid handler")
+	}
+
+	var ch = make(chan *gateway.TypingStartEvent, 1)
+	defer c.AddHandler(ch)()
+
+	state.Call(&gateway.TypingStartEvent{ChannelID: 1})
+
+	if ev := <-ch; ev.ChannelID != 1 {
+		t.Fatal("Unexpected event:", ev)
+	}
+}
+
+type testRaw struct {
+	Ctx    *Context
+	Return chan *gateway.RawEvent
+}
+
+func (t *testRaw) OnRaw(ev *gateway.RawEvent) {
+	t.Return <- ev
+}
+
+func TestRawEventHandler(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	var given = &testRaw{Return: make(chan *gateway.RawEvent, 1)}
+
+	c, err := New(state, given)
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	if err := c.callCmd(&gateway.RawEvent{Type: "NEW_FEATURE"}); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	if ev := <-given.Return; ev.Type != "NEW_FEATURE" {
+		t.Fatal("Unexpected event:", ev)
+	}
+}
+
+// sentConn is a wsutil.Connection that sends the payloads sent to it into a
+// channel.
+type sentConn chan string
+
+func (c sentConn) Dial(context.Context, string) error { return nil }
+func (c sentConn) Listen() <-chan wsutil.Event        { return nil }
+func (c sentConn) Close() error                       { return nil }
+
+func (c sentConn) Send(_ context.Context, b []byte) error {
+	c <- string(b)
+	return nil
+}
+
+func TestRotateStatus(t *testing.T) {
+	var g = gateway.NewCustomGateway("wss://localhost", "token")
+	var state = &state.State{
+		Session: &session.Session{Gateway: g},
+		Store:   state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	c.ErrorLogger = func(err error) { t.Error("Unexpected error:", err) }
+
+	// The Gateway isn't opened, so the status is only kept for identifying.
+	c.setStatus("offline")
+	if p := g.Identifier.Presence; p == nil || p.Game.Name != "offline" {
+		t.Fatal("Unexpected presence:", p)
+	}
+
+	var conn = make(sentConn)
+	g.WS.Conn = conn
+	g.WS.SendLimiter = rate.NewLimiter(rate.Inf, 1)
+
+	state.Store.GuildSet(&discord.Guild{ID: 1})
+	state.Store.GuildSet(&discord.Guild{ID: 2})
+
+	// A zero interval would panic in the ticker.
+	c.RotateStatus([]string{"~help"}, 0)()
+
+	stop := c.RotateStatus([]string{"~help", "in {guilds} servers"}, time.Millisecond)
+
+	for _, name := range []string{"~help", "in 2 servers", "~help"} {
+		if sent := <-conn; !strings.Contains(sent, `"name":"`+name+`"`) {
+			t.Fatal("Unexpected status:", sent)
+		}
+	}
+
+	stop()
+	stop()
+
+	// Drain the status that may have been sent while stopping.
+	select {
+	case <-conn:
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	select {
+	case sent := <-conn:
+		t.Fatal("Status sent after stopping:", sent)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestDeriveIntents(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	const expects = gateway.IntentGuildMessages | gateway.IntentDirectMessages |
+		gateway.IntentGuildMessageTyping | gateway.IntentDirectMessageTyping
+
+	if intents := c.DeriveIntents(); intents != expects {
+		t.Fatalf("Unexpected intents: %b", intents)
+	}
+}
+
+func TestCommandHooks(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	var started, ended *CommandContext
+	var endErr error
+
+	c.OnCommandStart = func(cmd *CommandContext, _ *gateway.MessageCreateEvent, _ []string) {
+		started = cmd
+	}
+	c.OnCommandEnd = func(
+		cmd *CommandContext, _ *gateway.MessageCreateEvent, _ time.Duration, err error) {
+
+		ended = cmd
+		endErr = err
+	}
+
+	c.callCmd(&gateway.MessageCreateEvent{
+		Message: discord.Message{Content: "!noArgs"},
+	})
+
+	if started == nil || started.MethodName != "NoArgs" {
+		t.Fatal("Unexpected started command:", started)
+	}
+	if ended != started {
+		t.Fatal("Unexpected ended command:", ended)
+	}
+	if endErr == nil || endErr.Error() != "passed" {
+		t.Fatal("Unexpected command error:", endErr)
+	}
+}
+
+func TestRegisterHelp(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.MustRegisterSubcommand(&testc{})
+
+	if err := c.RegisterHelp("help"); err != nil {
+		t.Fatal("Failed to register help:", err)
+	}
+	if err := c.RegisterHelp("help"); err == nil {
+		t.Fatal("Expected error for duplicate help")
+	}
+
+	if c.FindCommand("", "Help") == nil {
+		t.Fatal("Failed to find help command")
+	}
+
+	var mc = &gateway.MessageCreateEvent{}
+
+	var tests = []struct {
+		args   []string
+		expect string
+	}{
+		{[]string{"noArgs"}, "noArgs"},
+		{[]string{"testc", "noop"}, "testc noop"},
+		{[]string{"testc"}, "**testc**\n"},
+	}
+
+	for _, test := range tests {
+		h, err := c.helpFor(mc, test.args)
+		if err != nil {
+			t.Fatal("Unexpected help error:", err)
+		}
+		if !strings.HasPrefix(h, test.expect) {
+			t.Fatalf("Unexpected help for %v: %q", test.args, h)
+		}
+	}
+
+	if _, err := c.helpFor(mc, []string{"testc", "nope"}); err == nil {
+		t.Fatal("Expected unknown command error")
+	}
+}
+
+func TestNewPrefix(t *testing.T) {
+	var prefixer = NewPrefix("!", "?", "!!")
+
+	var tests = []struct {
+		content string
+		prefix  string
+		ok      bool
+	}{
+		{"!ping", "!", true},
+		{"?ping", "?", true},
+		{"!!ping", "!!", true},
+		{"ping", "", false},
+	}
+
+	for _, test := range tests {
+		m := &gateway.MessageCreateEvent{
+			Message: discord.Message{Content: test.content},
+		}
+
+		prefix, ok := prefixer(m)
+		if prefix != test.prefix || ok != test.ok {
+			t.Fatalf("Unexpected prefix %q (%v) for %q", prefix, ok, test.content)
+		}
+	}
+}
+
+func TestNewSelfPrefix(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+	state.Store.MyselfSet(&discord.User{ID: 69420})
+
+	var prefixer = NewSelfPrefix(state)
+
+	var tests = []struct {
+		content string
+		prefix  string
+		ok      bool
+	}{
+		{"<@69420> ping", "<@69420> ", true},
+		{"<@!69420>  ping", "<@!69420>  ", true},
+		{"<@69420>ping", "<@69420>", true},
+		{"<@1337> ping", "", false},
+		{"ping <@69420>", "", false},
+	}
+
+	for _, test := range tests {
+		m := &gateway.MessageCreateEvent{
+			Message: discord.Message{Content: test.content},
+		}
+
+		prefix, ok := prefixer(m)
+		if prefix != test.prefix || ok != test.ok {
+			t.Fatalf("Unexpected prefix %q (%v) for %q", prefix, ok, test.content)
+		}
+	}
+}
+
 func TestContext(t *testing.T) {
 	var given = &testc{}
 	var state = &state.State{
@@ -131,6 +515,29 @@ func TestContext(t *testing.T) {
 		}
 	})
 
+	t.Run("aliases", func(t *testing.T) {
+		if err := ctx.AddAliases("GetCounter", "gc", "Count"); err != nil {
+			t.Fatal("Failed to add aliases:", err)
+		}
+
+		if err := expect(ctx, given, "2", "pls do count"); err != nil {
+			t.Fatal("Unexpected error:", err)
+		}
+
+		if h := ctx.Help(); !strings.Contains(h, "getCounter (gc, count)") {
+			t.Fatal("Help doesn't contain aliases:", h)
+		}
+
+		// Aliases in a Raw subcommand are kept as they are.
+		raw := &Subcommand{Flag: Raw, Commands: []*CommandContext{{MethodName: "GetCounter"}}}
+		if err := raw.AddAliases("GetCounter", "Count"); err != nil {
+			t.Fatal("Failed to add aliases:", err)
+		}
+		if aliases := raw.Commands[0].Aliases; len(aliases) != 1 || aliases[0] != "Count" {
+			t.Fatal("Unexpected aliases:", aliases)
+		}
+	})
+
 	t.Run("typing event", func(t *testing.T) {
 		typing := &gateway.TypingStartEvent{}
 
@@ -184,6 +591,22 @@ func TestContext(t *testing.T) {
 		}
 	})
 
+	t.Run("call command variadic integers", func(t *testing.T) {
+		ctx.HasPrefix = NewPrefix("!")
+
+		if err := expect(ctx, given, 6, "!sum 1 2 3"); err != nil {
+			t.Fatal("Unexpected call error:", err)
+		}
+	})
+
+	t.Run("call command with context", func(t *testing.T) {
+		ctx.HasPrefix = NewPrefix("!")
+
+		if err := expect(ctx, given, "hime", "!withContext hime"); err != nil {
+			t.Fatal("Unexpected call error:", err)
+		}
+	})
+
 	t.Run("call command custom trailing manual parser", func(t *testing.T) {
 		ctx.HasPrefix = NewPrefix("!")
 		expects := []string{}
@@ -193,6 +616,24 @@ func TestContext(t *testing.T) {
 		}
 	})
 
+	t.Run("call command raw remainder", func(t *testing.T) {
+		ctx.HasPrefix = NewPrefix("!")
+		expects := RawRemainder(`free  "form" body`)
+
+		if err := expect(ctx, given, expects, `!remainder "a 'name'" free  "form" body`); err != nil {
+			t.Fatal("Unexpected call error:", err)
+		}
+
+		if err := expect(ctx, given, RawRemainder(""), "!remainder name"); err != nil {
+			t.Fatal("Unexpected call error:", err)
+		}
+
+		// Unbalanced quotes are fine in the remainder.
+		if err := expect(ctx, given, RawRemainder("it's cool"), "!remainder name it's cool"); err != nil {
+			t.Fatal("Unexpected call error:", err)
+		}
+	})
+
 	testMessage := func(content string) error {
 		// Mock a messageCreate event
 		m := &gateway.MessageCreateEvent{
@@ -222,34 +663,1051 @@ func TestContext(t *testing.T) {
 		if err == nil || !strings.HasPrefix(err.Error(), "Unknown command:") {
 			t.Fatal("unexpected error:", err)
 		}
+
+		ctx.FormatError = func(err error) string { return err.Error() }
+		ctx.FormatUnknownCommand = func(err *ErrUnknownCommand) string {
+			return "no " + err.Command + ", try " + err.Prefix + "help"
+		}
+
+		if s := ctx.formatError(err); s != "no no, try joe pls help" {
+			t.Fatal("unexpected formatted error:", s)
+		}
+
+		ctx.SuggestDistance = 2
+		defer func() { ctx.SuggestDistance = 0 }()
+
+		err = testMessage("joe pls sedn")
+		if err == nil || !strings.HasSuffix(err.Error(), "Did you mean: send?") {
+			t.Fatal("unexpected error:", err)
+		}
 	})
 
-	// Test subcommands
+	t.Run("call command invalid integer", func(t *testing.T) {
+		ctx.HasPrefix = NewPrefix("!")
 
-	t.Run("register subcommand", func(t *testing.T) {
-		ctx.HasPrefix = NewPrefix("run ")
+		err := testMessage("!sum 1 two")
 
-		sub := &testc{}
+		var usage *ErrInvalidUsage
+		if !errors.As(err, &usage) {
+			t.Fatal("unexpected error:", err)
+		}
 
-		_, err := ctx.RegisterSubcommand(sub)
-		if err != nil {
-			t.Fatal("Failed to register subcommand:", err)
+		if usage.Index != 2 {
+			t.Fatal("unexpected error index:", usage.Index)
 		}
 
-		if err := testMessage("run testc noop"); err != nil {
-			t.Fatal("Unexpected error:", err)
+		if s := usage.Wrap.Error(); s != "argument 2 (int): expected integer" {
+			t.Fatal("unexpected wrapped error:", s)
 		}
+	})
 
-		expects := RawArguments("hackadoll no. 3")
+	t.Run("call command default argument", func(t *testing.T) {
+		ctx.HasPrefix = NewPrefix("!")
 
-		if err := expect(ctx, sub, expects, "run testc content hackadoll no. 3"); err != nil {
+		cmd := ctx.FindCommand("", "Paged")
+		if err := cmd.SetDefaults("1"); err != nil {
+			t.Fatal("Failed to set defaults:", err)
+		}
+
+		if usage := cmd.Usage(); usage[1] != "[int]" {
+			t.Fatal("Unexpected usage:", usage)
+		}
+
+		if err := expect(ctx, given, 1, "!paged foods"); err != nil {
 			t.Fatal("Unexpected call error:", err)
 		}
 
-		if cmd := ctx.FindCommand("testc", "Noop"); cmd == nil {
-			t.Fatal("Failed to find subcommand Noop")
+		if err := expect(ctx, given, 3, "!paged foods 3"); err != nil {
+			t.Fatal("Unexpected call error:", err)
 		}
-	})
+
+		err := testMessage("!paged")
+		if !errors.Is(err, ErrNotEnoughArgs) {
+			t.Fatal("Unexpected error:", err)
+		}
+
+		const usage = "paged requires 1 to 2 arguments (got 0): usage: paged string [int]"
+		if err.Error() != usage {
+			t.Fatal("Unexpected error string:", err)
+		}
+
+		err = testMessage("!paged foods 3 4")
+		if !errors.Is(err, ErrTooManyArgs) {
+			t.Fatal("Unexpected error:", err)
+		}
+
+		if err := cmd.SetDefaults("one"); err == nil {
+			t.Fatal("Expected error for invalid default")
+		}
+	})
+
+	// Test subcommands
+
+	t.Run("register subcommand", func(t *testing.T) {
+		ctx.HasPrefix = NewPrefix("run ")
+
+		sub := &testc{}
+
+		_, err := ctx.RegisterSubcommand(sub)
+		if err != nil {
+			t.Fatal("Failed to register subcommand:", err)
+		}
+
+		if err := testMessage("run testc noop"); err != nil {
+			t.Fatal("Unexpected error:", err)
+		}
+
+		expects := RawArguments("hackadoll no. 3")
+
+		if err := expect(ctx, sub, expects, "run testc content hackadoll no. 3"); err != nil {
+			t.Fatal("Unexpected call error:", err)
+		}
+
+		if cmd := ctx.FindCommand("testc", "Noop"); cmd == nil {
+			t.Fatal("Failed to find subcommand Noop")
+		}
+	})
+
+	t.Run("register subcommand value", func(t *testing.T) {
+		ctx.HasPrefix = NewPrefix("run ")
+
+		sub, err := ctx.RegisterSubcommandValue("Other", given)
+		if err != nil {
+			t.Fatal("Failed to register subcommand:", err)
+		}
+
+		if sub.StructName != "Other" || sub.Command != "other" {
+			t.Fatal("Unexpected names:", sub.StructName, sub.Command)
+		}
+
+		expects := RawArguments("shared")
+
+		if err := expect(ctx, given, expects, "run other content shared"); err != nil {
+			t.Fatal("Unexpected call error:", err)
+		}
+
+		if _, err := ctx.RegisterSubcommandValue("Other", given); err == nil {
+			t.Fatal("Expected error for duplicate name")
+		}
+	})
+}
+
+func TestCommandPermissions(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	state.Store.GuildSet(&discord.Guild{
+		ID:      1,
+		OwnerID: 2,
+		Roles: []discord.Role{
+			{ID: 1, Permissions: discord.PermissionSendMessages},
+			{ID: 3, Permissions: discord.PermissionManageMessages},
+		},
+	})
+	state.Store.ChannelSet(&discord.Channel{ID: 4, GuildID: 1})
+	state.Store.MemberSet(1, &discord.Member{User: discord.User{ID: 5}})
+	state.Store.MemberSet(1, &discord.Member{
+		User:    discord.User{ID: 6},
+		RoleIDs: []discord.Snowflake{3},
+	})
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+	c.FindCommand("", "NoArgs").Permissions = discord.PermissionManageMessages
+
+	call := func(guildID, userID discord.Snowflake) error {
+		return c.callCmd(&gateway.MessageCreateEvent{
+			Message: discord.Message{
+				Content:   "!noArgs",
+				GuildID:   guildID,
+				ChannelID: 4,
+				Author:    discord.User{ID: userID},
+			},
+		})
+	}
+
+	var perms *ErrMissingPermissions
+	if err := call(1, 5); !errors.As(err, &perms) {
+		t.Fatal("Unexpected error:", err)
+	}
+	if perms.Missing != discord.PermissionManageMessages {
+		t.Fatal("Unexpected missing permissions:", perms.Missing)
+	}
+
+	if err := call(1, 6); err == nil || err.Error() != "passed" {
+		t.Fatal("Unexpected error with permissions:", err)
+	}
+
+	// Direct messages don't have permissions.
+	if err := call(0, 5); err == nil || err.Error() != "passed" {
+		t.Fatal("Unexpected error in direct message:", err)
+	}
+
+	var denied *CommandContext
+	c.OnPermissionDenied = func(m *gateway.MessageCreateEvent, cmd *CommandContext) {
+		denied = cmd
+	}
+
+	var cmd = c.FindCommand("", "NoArgs")
+	cmd.Permissions = 0
+	cmd.SetFlag(AdminOnly)
+
+	if err := call(1, 6); err != nil || denied != cmd {
+		t.Fatal("Expected permission to be denied:", err)
+	}
+
+	denied = nil
+
+	// The owner is an administrator.
+	state.Store.MemberSet(1, &discord.Member{User: discord.User{ID: 2}})
+	if err := call(1, 2); err == nil || err.Error() != "passed" || denied != nil {
+		t.Fatal("Unexpected error from owner:", err)
+	}
+}
+
+func TestCommandChannelFlags(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	call := func(guildID discord.Snowflake) error {
+		return c.callCmd(&gateway.MessageCreateEvent{
+			Message: discord.Message{
+				Content:   "!noArgs",
+				GuildID:   guildID,
+				ChannelID: 4,
+			},
+		})
+	}
+
+	var cmd = c.FindCommand("", "NoArgs")
+
+	cmd.SetFlag(GuildOnly)
+	if err := call(0); !errors.As(err, new(*ErrGuildOnly)) {
+		t.Fatal("Unexpected error in direct message:", err)
+	}
+	if err := call(1); err == nil || err.Error() != "passed" {
+		t.Fatal("Unexpected error in guild:", err)
+	}
+
+	cmd.ClearFlag(GuildOnly)
+	cmd.SetFlag(DMOnly)
+	if err := call(1); !errors.As(err, new(*ErrDMOnly)) {
+		t.Fatal("Unexpected error in guild:", err)
+	}
+	if err := call(0); err == nil || err.Error() != "passed" {
+		t.Fatal("Unexpected error in direct message:", err)
+	}
+
+	// These are the user's mistakes, so they're not logged.
+	c.ReplyError = false
+	c.ErrorLogger = func(err error) { t.Error("Unexpected logged error:", err) }
+	c.handleError(&gateway.MessageCreateEvent{}, &ErrGuildOnly{Ctx: cmd})
+	c.handleError(&gateway.MessageCreateEvent{}, &ErrDMOnly{Ctx: cmd})
+
+	if help := c.Help(); !strings.Contains(help, "(DMs only)") {
+		t.Fatal("Help doesn't mention DMOnly:", help)
+	}
+}
+
+func TestCommandEnabled(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+	c.ReplyError = true
+	c.MustRegisterSubcommand(&testNested{Name: "nested"})
+
+	// Guild 1 has every command disabled.
+	var checked []string
+	c.CommandEnabled = func(guildID discord.Snowflake, cmd *CommandContext) bool {
+		checked = append(checked, cmd.MethodName)
+		return guildID != 1
+	}
+
+	call := func(content string, guildID discord.Snowflake) error {
+		return c.callCmd(&gateway.MessageCreateEvent{
+			Message: discord.Message{Content: content, GuildID: guildID},
+		})
+	}
+
+	var disabled *ErrCommandDisabled
+	if err := call("!noArgs", 1); !errors.As(err, &disabled) || disabled.Ctx.MethodName != "NoArgs" {
+		t.Fatal("Unexpected error in disabled guild:", err)
+	}
+	if err := call("!testNested set a", 1); !errors.As(err, new(*ErrCommandDisabled)) {
+		t.Fatal("Unexpected error for subcommand in disabled guild:", err)
+	}
+	if err := call("!noArgs", 2); err == nil || err.Error() != "passed" {
+		t.Fatal("Unexpected error in enabled guild:", err)
+	}
+
+	// Direct messages aren't checked.
+	if err := call("!noArgs", 0); err == nil || err.Error() != "passed" {
+		t.Fatal("Unexpected error in direct message:", err)
+	}
+
+	if strings.Join(checked, ",") != "NoArgs,Set,NoArgs" {
+		t.Fatal("Unexpected checked commands:", checked)
+	}
+
+	old := CommandDisabledString
+	CommandDisabledString = func(*ErrCommandDisabled) string { return "" }
+	defer func() { CommandDisabledString = old }()
+
+	replies, err := c.SimulateMessage(&gateway.MessageCreateEvent{
+		Message: discord.Message{Content: "!noArgs", GuildID: 1},
+	})
+	if !errors.As(err, new(*ErrCommandDisabled)) || len(replies) != 0 {
+		t.Fatal("Unexpected reply to disabled command:", replies, err)
+	}
+}
+
+func TestAllCommands(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	if _, err := c.RegisterSubcommandValue("sub", &testc{}); err != nil {
+		t.Fatal("Failed to register subcommand:", err)
+	}
+
+	var infos = c.AllCommands()
+	if len(infos) != 2*len(c.Commands) {
+		t.Fatal("Unexpected number of commands:", len(infos))
+	}
+
+	var found bool
+	for _, info := range infos {
+		if info.Subcommand == "sub" && info.Command == "paged" {
+			found = true
+
+			if !reflect.DeepEqual(info.Usage, []string{"string", "int"}) {
+				t.Fatal("Unexpected usage:", info.Usage)
+			}
+		}
+	}
+
+	if !found {
+		t.Fatal("Subcommand command not found")
+	}
+}
+
+func TestIgnoreSelf(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+	state.Store.MyselfSet(&discord.User{ID: 1, Bot: true})
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+	c.AllowBot = true
+
+	call := func(userID discord.Snowflake) error {
+		return c.callCmd(&gateway.MessageCreateEvent{
+			Message: discord.Message{
+				Content: "!noArgs",
+				Author:  discord.User{ID: userID, Bot: true},
+			},
+		})
+	}
+
+	if err := call(1); err != nil {
+		t.Fatal("Unexpected error from self:", err)
+	}
+	if err := call(2); err == nil || err.Error() != "passed" {
+		t.Fatal("Unexpected error from other bot:", err)
+	}
+
+	c.AllowSelf = true
+	if err := call(1); err == nil || err.Error() != "passed" {
+		t.Fatal("Unexpected error from self:", err)
+	}
+}
+
+type testContentEmbed struct {
+	Ctx *Context
+}
+
+func (t *testContentEmbed) Both(_ *gateway.MessageCreateEvent) (string, *discord.Embed, error) {
+	return "content", &discord.Embed{Title: "embed"}, nil
+}
+
+func (t *testContentEmbed) Neither(_ *gateway.MessageCreateEvent) (string, *discord.Embed, error) {
+	return "", nil, nil
+}
+
+func TestCommandContentEmbed(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testContentEmbed{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	var mc = &gateway.MessageCreateEvent{}
+
+	v, err := c.callCommand(c.FindCommand("", "Both"), mc)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	data, ok := v.(*api.SendMessageData)
+	if !ok || data.Content != "content" || data.Embed == nil || data.Embed.Title != "embed" {
+		t.Fatalf("Unexpected reply: %#v", v)
+	}
+
+	if v, err := c.callCommand(c.FindCommand("", "Neither"), mc); v != nil || err != nil {
+		t.Fatal("Unexpected reply:", v, err)
+	}
+}
+
+type testArguments struct {
+	Ctx    *Context
+	Return chan interface{}
+}
+
+func (t *testArguments) Forward(_ *gateway.MessageCreateEvent, args Arguments, n int, rest ...string) {
+	t.Return <- fmt.Sprint(args, n, rest)
+}
+
+func TestCommandArguments(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	var given = &testArguments{Return: make(chan interface{}, 1)}
+
+	c, err := New(state, given)
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	if usage := c.FindCommand("", "Forward").Usage(); len(usage) != 2 {
+		t.Fatal("Unexpected usage:", usage)
+	}
+
+	err = c.callCmd(&gateway.MessageCreateEvent{
+		Message: discord.Message{Content: "!forward 2 a b"},
+	})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	if v := <-given.Return; v != "[2 a b] 2 [a b]" {
+		t.Fatal("Unexpected arguments:", v)
+	}
+}
+
+func TestCommandDelimiter(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	var given = &testArguments{Return: make(chan interface{}, 1)}
+
+	c, err := New(state, given)
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	var cmd = c.FindCommand("", "Forward")
+	cmd.Delimiter = ","
+
+	if usage := strings.Join(cmd.Usage(), " "); usage != "int, string" {
+		t.Fatal("Unexpected usage:", usage)
+	}
+
+	err = c.callCmd(&gateway.MessageCreateEvent{
+		Message: discord.Message{Content: "!forward 2 ,  red apple , \"blue\""},
+	})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	if v := <-given.Return; v != `[2 red apple "blue"] 2 [red apple "blue"]` {
+		t.Fatal("Unexpected arguments:", v)
+	}
+
+	// Apostrophes aren't quotes with a delimiter.
+	err = c.callCmd(&gateway.MessageCreateEvent{
+		Message: discord.Message{Content: "!forward 2, Who's best?, me, you"},
+	})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	if v := <-given.Return; v != "[2 Who's best? me you] 2 [Who's best? me you]" {
+		t.Fatal("Unexpected arguments:", v)
+	}
+
+	err = c.callCmd(&gateway.MessageCreateEvent{
+		Message: discord.Message{Content: "!forward two, a"},
+	})
+	if err == nil {
+		t.Fatal("Expected an error for an invalid argument")
+	}
+}
+
+func TestCommandVariadicBounds(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	var given = &testArguments{Return: make(chan interface{}, 1)}
+
+	c, err := New(state, given)
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	var cmd = c.FindCommand("", "Forward")
+	if err := cmd.SetVariadicBounds(1, 2); err != nil {
+		t.Fatal("Failed to set bounds:", err)
+	}
+
+	if usage := strings.Join(cmd.Usage(), " "); usage != "int string... (1 to 2)" {
+		t.Fatal("Unexpected usage:", usage)
+	}
+
+	call := func(content string) error {
+		return c.callCmd(&gateway.MessageCreateEvent{
+			Message: discord.Message{Content: content},
+		})
+	}
+
+	if err := call("!forward 2 a b"); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if v := <-given.Return; v != "[2 a b] 2 [a b]" {
+		t.Fatal("Unexpected arguments:", v)
+	}
+
+	err = call("!forward 2")
+	if !errors.Is(err, ErrNotEnoughArgs) {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	const usage = "forward requires 2 to 3 arguments (got 1): usage: forward int string... (1 to 2)"
+	if err.Error() != usage {
+		t.Fatal("Unexpected error string:", err)
+	}
+
+	if err := call("!forward 2 a b c"); !errors.Is(err, ErrTooManyArgs) {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	if err := cmd.SetVariadicBounds(3, 2); err == nil {
+		t.Fatal("Expected error for invalid bounds")
+	}
+
+	// Only variadic parameters can be bounded.
+	if err := (&CommandContext{}).SetVariadicBounds(1, 0); err == nil {
+		t.Fatal("Expected error for a command without variadic arguments")
+	}
+}
+
+type testNested struct {
+	Ctx  *Context
+	Name string
+}
+
+func (t *testNested) Set(_ *gateway.MessageCreateEvent, value string) error {
+	return errors.New(t.Name + " " + value)
+}
+
+func TestNestedSubcommands(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	config, err := c.RegisterSubcommandValue("Config", &testNested{Name: "config"})
+	if err != nil {
+		t.Fatal("Failed to register subcommand:", err)
+	}
+
+	server, err := config.RegisterSubcommandValue("GーServer", &testNested{Name: "server"})
+	if err != nil {
+		t.Fatal("Failed to register nested subcommand:", err)
+	}
+
+	prefix, err := server.RegisterSubcommandValue("Prefix", &testNested{Name: "prefix"})
+	if err != nil {
+		t.Fatal("Failed to register nested subcommand:", err)
+	}
+
+	if !prefix.Flag.Is(GuildOnly) || !prefix.FindCommand("Set").Flag.Is(GuildOnly) {
+		t.Fatal("Nested subcommand didn't inherit the GuildOnly flag")
+	}
+	if config.FindCommand("Set").Flag.Is(GuildOnly) {
+		t.Fatal("Parent subcommand got its child's flag")
+	}
+
+	if _, err := (&Subcommand{}).RegisterSubcommand(&testNested{}); err == nil {
+		t.Fatal("Expected error for an unregistered parent")
+	}
+	if _, err := config.RegisterSubcommandValue("Server", &testNested{}); err == nil {
+		t.Fatal("Expected error for duplicate name")
+	}
+
+	testMessage := func(content string) error {
+		return c.callCmd(&gateway.MessageCreateEvent{
+			Message: discord.Message{Content: content, GuildID: 1},
+		})
+	}
+
+	var tests = []struct {
+		content string
+		err     string
+	}{
+		{"!config set a", "config a"},
+		{"!config server set b", "server b"},
+		{"!config server prefix set !", "prefix !"},
+		{"!config server prefix sett !", "Unknown command: !config server prefix sett. Did you mean: set?"},
+		{"!config server prefix", "Unknown command: !config server prefix"},
+	}
+
+	for _, test := range tests {
+		if err := testMessage(test.content); err == nil || err.Error() != test.err {
+			t.Errorf("Unexpected error for %q: %v", test.content, err)
+		}
+	}
+
+	var dm = &gateway.MessageCreateEvent{
+		Message: discord.Message{Content: "!config server prefix set !"},
+	}
+	if err := c.callCmd(dm); !errors.As(err, new(*ErrGuildOnly)) {
+		t.Fatal("Unexpected error in DMs:", err)
+	}
+
+	var names []string
+	for _, cmd := range c.AllCommands() {
+		if cmd.Command == "set" {
+			names = append(names, cmd.Subcommand)
+		}
+	}
+	if strings.Join(names, ",") != "config,config server,config server prefix" {
+		t.Fatal("Unexpected subcommand names:", names)
+	}
+
+	if help := c.Help(); !strings.Contains(help, "config server prefix set") {
+		t.Fatal("Help is missing the nested command:", help)
+	}
+
+	// Each subcommand is listed once, under its parent.
+	for _, header := range []string{"**config**", "**server**", "**prefix**"} {
+		if n := strings.Count(c.Help(), header); n != 1 {
+			t.Fatalf("Help lists %s %d times: %s", header, n, c.Help())
+		}
+	}
+
+	help, err := c.helpFor(dm, []string{"config", "server", "prefix", "set"})
+	if err != nil || !strings.HasPrefix(help, "config server prefix set") {
+		t.Fatal("Unexpected command help:", help, err)
+	}
+
+	if cmd := c.FindCommand("GーServer", "Set"); cmd == nil {
+		t.Fatal("Failed to find the nested command")
+	}
+}
+
+func TestUnregisterSubcommand(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	config := c.MustRegisterSubcommand(&testNested{Name: "config"})
+	config.MustRegisterSubcommand(&testNested{Name: "server"})
+
+	testMessage := func(content string) error {
+		return c.callCmd(&gateway.MessageCreateEvent{
+			Message: discord.Message{Content: content},
+		})
+	}
+
+	// Commands keep being called while the subcommand is removed.
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			testMessage("!testNested set a")
+		}
+	}()
+
+	if !config.UnregisterSubcommand("testNested") {
+		t.Fatal("Failed to unregister the nested subcommand")
+	}
+	if !c.UnregisterSubcommand("testNested") {
+		t.Fatal("Failed to unregister the subcommand")
+	}
+
+	<-done
+
+	if c.UnregisterSubcommand("testNested") {
+		t.Fatal("Unregistered a subcommand twice")
+	}
+
+	if err := testMessage("!testNested set a"); !errors.As(err, new(*ErrUnknownCommand)) {
+		t.Fatal("Unexpected error after unregistering:", err)
+	}
+	if len(c.Subcommands()) != 0 {
+		t.Fatal("Unexpected subcommands:", c.Subcommands())
+	}
+}
+
+type testEvents struct {
+	Ctx    *Context
+	Typing chan struct{}
+}
+
+func (t *testEvents) OnTyping(*gateway.TypingStartEvent) {
+	t.Typing <- struct{}{}
+}
+
+func TestUnregisterSubcommandEvents(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	var events = &testEvents{Typing: make(chan struct{}, 1)}
+	c.MustRegisterSubcommand(events)
+
+	if err := c.callCmd(&gateway.TypingStartEvent{}); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	select {
+	case <-events.Typing:
+	default:
+		t.Fatal("Event handler wasn't called")
+	}
+
+	if !c.UnregisterSubcommand("testEvents") {
+		t.Fatal("Failed to unregister the subcommand")
+	}
+
+	if err := c.callCmd(&gateway.TypingStartEvent{}); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	select {
+	case <-events.Typing:
+		t.Fatal("Event handler was called after unregistering")
+	default:
+	}
+}
+
+type testContent struct {
+	Ctx *Context
+}
+
+func (t *testContent) Eval(_ *gateway.MessageCreateEvent, code RawContent) error {
+	return errors.New(string(code))
+}
+
+type testContentInvalid struct {
+	Ctx *Context
+}
+
+func (t *testContentInvalid) Eval(_ *gateway.MessageCreateEvent, n int, code RawContent) {}
+
+func TestCommandRawContent(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testContent{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	var tests = []struct {
+		content string
+		code    string
+	}{
+		{"!eval\n    indented\n", "    indented\n"},
+		{"!eval  two spaces ", " two spaces "},
+		{"!eval print(\"it's\")", "print(\"it's\")"},
+		{"!eval", ""},
+	}
+
+	for _, test := range tests {
+		err := c.callCmd(&gateway.MessageCreateEvent{
+			Message: discord.Message{Content: test.content},
+		})
+		if err == nil || err.Error() != test.code {
+			t.Errorf("Unexpected content for %q: %q", test.content, err)
+		}
+	}
+
+	// Other commands still fail on unbalanced quotes.
+	err = c.callCmd(&gateway.MessageCreateEvent{
+		Message: discord.Message{Content: "!unknown it's"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "Failed to parse command") {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	if _, err := New(state, &testContentInvalid{}); err == nil {
+		t.Fatal("Expected error for RawContent after another argument")
+	}
+}
+
+type testCleanUp struct {
+	Ctx *Context
+}
+
+func (t *testCleanUp) Clean(*gateway.MessageCreateEvent) (string, error) {
+	return "cleaned", nil
+}
+
+func (t *testCleanUp) Long(*gateway.MessageCreateEvent) (string, error) {
+	return strings.Repeat("cleaned ", MessageMax/4), nil
+}
+
+func TestCommandCleanUp(t *testing.T) {
+	var reply = discord.NewSnowflakeFromTime(time.Now())
+	var deleted = make(chan []discord.Snowflake, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/bulk-delete") {
+			var body struct {
+				Messages []discord.Snowflake `json:"messages"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+
+			deleted <- body.Messages
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if r.Method == "DELETE" {
+			id, _ := discord.ParseSnowflake(path.Base(r.URL.Path))
+			deleted <- []discord.Snowflake{id}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		json.NewEncoder(w).Encode(discord.Message{ID: reply})
+	}))
+	defer srv.Close()
+
+	old := api.EndpointChannels
+	api.EndpointChannels = srv.URL + api.APIPath + "/channels/"
+	defer func() { api.EndpointChannels = old }()
+
+	var state = &state.State{
+		Session: &session.Session{Client: api.NewClient("")},
+		Store:   state.NewDefaultStore(nil),
+	}
+
+	// The bot owns the guild, so it can manage messages.
+	state.Store.MyselfSet(&discord.User{ID: 2})
+	state.Store.GuildSet(&discord.Guild{ID: 1, OwnerID: 2})
+	state.Store.ChannelSet(&discord.Channel{ID: 4, GuildID: 1})
+	state.Store.MemberSet(1, &discord.Member{User: discord.User{ID: 2}})
+
+	c, err := New(state, &testCleanUp{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("~")
+
+	c.ErrorLogger = func(err error) { t.Error(err) }
+
+	cmd := c.FindCommand("", "Clean")
+	cmd.DeleteReplyAfter = 10 * time.Millisecond
+	cmd.DeleteInvocation = true
+
+	var invoke = reply - 1
+
+	err = c.callCmd(&gateway.MessageCreateEvent{
+		Message: discord.Message{
+			ID:        invoke,
+			Content:   "~clean",
+			GuildID:   1,
+			ChannelID: 4,
+			Author:    discord.User{ID: 3},
+		},
+	})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	select {
+	case ids := <-deleted:
+		if len(ids) != 2 || ids[0] != reply || ids[1] != invoke {
+			t.Fatal("Unexpected deleted messages:", ids)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Messages weren't deleted")
+	}
+
+	cmd = c.FindCommand("", "Long")
+	cmd.DeleteReplyAfter = 10 * time.Millisecond
+	cmd.DeleteInvocation = true
+
+	// Bots can't bulk delete in DMs, nor delete the invocation there, so the
+	// two replies are deleted one by one.
+	err = c.callCmd(&gateway.MessageCreateEvent{
+		Message: discord.Message{
+			ID:        invoke,
+			Content:   "~long",
+			ChannelID: 5,
+			Author:    discord.User{ID: 3},
+		},
+	})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case ids := <-deleted:
+			if len(ids) != 1 || ids[0] != reply {
+				t.Fatal("Unexpected deleted messages:", ids)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Replies weren't deleted")
+		}
+	}
+}
+
+type testPanic struct {
+	Ctx *Context
+}
+
+func (t *testPanic) Panic(_ *gateway.MessageCreateEvent) {
+	panic("oh no")
+}
+
+func TestCommandPanic(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testPanic{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	var recovered interface{}
+	c.OnPanic = func(v interface{}) { recovered = v }
+
+	err = c.callCmd(&gateway.MessageCreateEvent{
+		Message: discord.Message{Content: "!panic"},
+	})
+
+	var p *ErrPanic
+	if !errors.As(err, &p) {
+		t.Fatal("Unexpected error:", err)
+	}
+	if err.Error() != "Command panicked: oh no" {
+		t.Fatal("Unexpected error string:", err)
+	}
+	if recovered != "oh no" {
+		t.Fatal("OnPanic was not called:", recovered)
+	}
+}
+
+type testMiddleware struct {
+	Ctx    *Context
+	Called bool
+}
+
+func (t *testMiddleware) MーBlock(m *gateway.MessageCreateEvent) error {
+	if m.Content == "!testMiddleware run blocked" {
+		return errors.New("blocked")
+	}
+	return nil
+}
+
+func (t *testMiddleware) MーOnTyping(*gateway.TypingStartEvent) {}
+
+func (t *testMiddleware) Run(_ *gateway.MessageCreateEvent, s string) {
+	t.Called = true
+}
+
+func TestMiddlewareAbort(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	var sub = &testMiddleware{}
+	c.MustRegisterSubcommand(sub)
+
+	call := func(content string) error {
+		return c.callCmd(&gateway.MessageCreateEvent{
+			Message: discord.Message{Content: content},
+		})
+	}
+
+	if err := call("!testMiddleware run blocked"); err == nil || err.Error() != "blocked" {
+		t.Fatal("Unexpected error:", err)
+	}
+	if sub.Called {
+		t.Fatal("Command was called despite the middleware failing")
+	}
+
+	if err := call("!testMiddleware run fine"); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if !sub.Called {
+		t.Fatal("Command was not called")
+	}
 }
 
 func expect(ctx *Context, given *testc, expects interface{}, content string) (call error) {
@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"sync"
+
+	"github.com/diamondburned/arikawa/discord"
+)
+
+// DispatchMode decides whether events are handled concurrently or one after
+// another. Refer to Context's Dispatch.
+type DispatchMode uint8
+
+const (
+	// DispatchConcurrent handles each event as soon as the State's Handler
+	// calls the Context, which is in its own goroutine unless the Handler is
+	// Synchronous. This is the default.
+	DispatchConcurrent DispatchMode = iota
+	// DispatchSynchronous handles one event at a time, in the order that they
+	// reach the Context.
+	DispatchSynchronous
+	// DispatchPerChannel handles one event at a time for each channel, so
+	// replies in a channel come back in order, while different channels are
+	// still handled in parallel. Events without a channel are handled
+	// concurrently.
+	DispatchPerChannel
+)
+
+// dispatcher runs the functions queued with the same key one after another,
+// each key in its own goroutine. The goroutine exits once its queue is empty.
+type dispatcher struct {
+	mutex  sync.Mutex
+	queues map[discord.Snowflake][]func()
+}
+
+// run queues fn for the key, starting a goroutine for the key if none is
+// running.
+func (d *dispatcher) run(key discord.Snowflake, fn func()) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.queues == nil {
+		d.queues = map[discord.Snowflake][]func(){}
+	}
+
+	queue, running := d.queues[key]
+	d.queues[key] = append(queue, fn)
+
+	if !running {
+		go d.drain(key)
+	}
+}
+
+// drain runs the key's queue until it's empty.
+func (d *dispatcher) drain(key discord.Snowflake) {
+	for {
+		d.mutex.Lock()
+		queue := d.queues[key]
+		if len(queue) == 0 {
+			delete(d.queues, key)
+			d.mutex.Unlock()
+			return
+		}
+		d.queues[key] = queue[1:]
+		d.mutex.Unlock()
+
+		queue[0]()
+	}
+}
@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDispatcherOrder(t *testing.T) {
+	var d dispatcher
+	var wg sync.WaitGroup
+	var got []int
+
+	for i := 0; i < 100; i++ {
+		i := i
+		wg.Add(1)
+
+		d.run(1, func() {
+			defer wg.Done()
+			got = append(got, i) // only one goroutine runs key 1
+		})
+	}
+
+	wg.Wait()
+
+	for i, n := range got {
+		if i != n {
+			t.Fatal("Out of order at", i, "got", n)
+		}
+	}
+
+	// The goroutine should be gone once the queue is drained.
+	time.Sleep(10 * time.Millisecond)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if len(d.queues) != 0 {
+		t.Fatal("Queue wasn't removed:", d.queues)
+	}
+}
+
+func TestDispatcherParallel(t *testing.T) {
+	var d dispatcher
+	var done = make(chan struct{})
+	var unblock = make(chan struct{})
+
+	// Key 1 blocks until key 2 runs, which only works if they're parallel.
+	d.run(1, func() {
+		<-unblock
+		close(done)
+	})
+	d.run(2, func() { close(unblock) })
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Different keys didn't run in parallel")
+	}
+}
@@ -2,7 +2,12 @@ package bot
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/discord"
 )
 
 type ErrUnknownCommand struct {
@@ -10,6 +15,10 @@ type ErrUnknownCommand struct {
 	Command string
 	Parent  string
 
+	// Suggestion is the closest known command name, or empty if there's none.
+	// Refer to Context's SuggestDistance.
+	Suggestion string
+
 	// TODO: list available commands?
 	// Here, as a reminder
 	ctx []*CommandContext
@@ -27,9 +36,17 @@ var UnknownCommandString = func(err *ErrUnknownCommand) string {
 		header += err.Command
 	}
 
+	if err.Suggestion != "" {
+		header += ". Did you mean: " + err.Suggestion + "?"
+	}
+
 	return header
 }
 
+// ErrCommandNotFound is returned by FindCommandErr if there's no command with
+// the given method name.
+var ErrCommandNotFound = errors.New("command not found")
+
 var (
 	ErrTooManyArgs   = errors.New("Too many arguments given")
 	ErrNotEnoughArgs = errors.New("Not enough arguments given")
@@ -55,6 +72,11 @@ func (err *ErrInvalidUsage) Unwrap() error {
 }
 
 var InvalidUsageString = func(err *ErrInvalidUsage) string {
+	var count *ErrArgumentCount
+	if errors.As(err.Wrap, &count) {
+		return count.Error()
+	}
+
 	if err.Index == 0 {
 		return "Invalid usage, error: " + err.Wrap.Error() + "."
 	}
@@ -79,3 +101,198 @@ var InvalidUsageString = func(err *ErrInvalidUsage) string {
 
 	return body
 }
+
+// ErrArgumentCount is wrapped in ErrInvalidUsage when a command is given too
+// few or too many arguments. It unwraps to either ErrNotEnoughArgs or
+// ErrTooManyArgs.
+type ErrArgumentCount struct {
+	// Command is the full command name, such as "sub send".
+	Command string
+	// Got is the number of arguments given.
+	Got  int
+	Wrap error
+	Ctx  *CommandContext
+}
+
+func (err *ErrArgumentCount) Error() string {
+	return ArgumentCountString(err)
+}
+
+func (err *ErrArgumentCount) Unwrap() error {
+	return err.Wrap
+}
+
+// ArgumentCountString formats ErrArgumentCount, for example:
+//
+//    send requires 2 arguments (got 1): usage: send string int
+//
+var ArgumentCountString = func(err *ErrArgumentCount) string {
+	var required = 0
+	for _, arg := range err.Ctx.Arguments {
+		if !arg.Optional {
+			required++
+		}
+	}
+
+	var max = len(err.Ctx.Arguments)
+	var unbounded = err.Ctx.Variadic
+
+	// The last argument of variadic commands may be empty, unless it's
+	// bounded.
+	if err.Ctx.Variadic && required == max {
+		required--
+		required += err.Ctx.variadicMin
+	}
+	if err.Ctx.variadicMax > 0 {
+		max += err.Ctx.variadicMax - 1
+		unbounded = false
+	}
+
+	var count string
+	switch {
+	case unbounded:
+		count = "at least " + plural(required, "argument")
+	case required == max:
+		count = plural(required, "argument")
+	default:
+		count = strconv.Itoa(required) + " to " + plural(max, "argument")
+	}
+
+	var usage = err.Command
+	for _, arg := range err.Ctx.Usage() {
+		usage += " " + arg
+	}
+	if err.Ctx.Variadic && !err.Ctx.bounded() {
+		usage += "..."
+	}
+
+	return err.Command + " requires " + count +
+		" (got " + strconv.Itoa(err.Got) + "): usage: " + usage
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return strconv.Itoa(n) + " " + word + "s"
+}
+
+// ErrPanic is returned when a command or handler panics. The panic is recovered
+// and passed to Context.OnPanic first.
+type ErrPanic struct {
+	Value interface{}
+	Ctx   *CommandContext
+}
+
+func (err *ErrPanic) Error() string {
+	return PanicString(err)
+}
+
+var PanicString = func(err *ErrPanic) string {
+	return fmt.Sprintf("Command panicked: %v", err.Value)
+}
+
+// ErrOnCooldown is returned when a command is invoked before its cooldown has
+// expired.
+type ErrOnCooldown struct {
+	Remaining time.Duration
+	Ctx       *CommandContext
+}
+
+func (err *ErrOnCooldown) Error() string {
+	return OnCooldownString(err)
+}
+
+var OnCooldownString = func(err *ErrOnCooldown) string {
+	// Round up, so "0 seconds" is never shown.
+	secs := int64((err.Remaining + time.Second - 1) / time.Second)
+	if secs == 1 {
+		return "Please wait 1 second before using this command again."
+	}
+
+	return "Please wait " + strconv.FormatInt(secs, 10) +
+		" seconds before using this command again."
+}
+
+// ErrMissingPermissions is returned when the invoking member doesn't have the
+// permissions required by a command.
+type ErrMissingPermissions struct {
+	// Missing contains the required permissions that the member doesn't have.
+	Missing discord.Permissions
+	Ctx     *CommandContext
+}
+
+func (err *ErrMissingPermissions) Error() string {
+	return MissingPermissionsString(err)
+}
+
+var MissingPermissionsString = func(err *ErrMissingPermissions) string {
+	return "You don't have the permissions required to use this command."
+}
+
+// ErrGuildOnly is returned when a GuildOnly command is used outside of guilds.
+type ErrGuildOnly struct {
+	Ctx *CommandContext
+}
+
+func (err *ErrGuildOnly) Error() string {
+	return GuildOnlyString(err)
+}
+
+var GuildOnlyString = func(err *ErrGuildOnly) string {
+	return "This command can only be used in a server."
+}
+
+// ErrDMOnly is returned when a DMOnly command is used in a guild.
+type ErrDMOnly struct {
+	Ctx *CommandContext
+}
+
+func (err *ErrDMOnly) Error() string {
+	return DMOnlyString(err)
+}
+
+var DMOnlyString = func(err *ErrDMOnly) string {
+	return "This command can only be used in direct messages."
+}
+
+// ErrCommandDisabled is returned when a command is used in a guild that has it
+// disabled, according to the Context's CommandEnabled. Make
+// CommandDisabledString return an empty string to not reply to the user.
+type ErrCommandDisabled struct {
+	Ctx *CommandContext
+}
+
+func (err *ErrCommandDisabled) Error() string {
+	return CommandDisabledString(err)
+}
+
+var CommandDisabledString = func(err *ErrCommandDisabled) string {
+	return "This command is disabled in this server."
+}
+
+// ShutdownErrors is returned by Close when more than one shutdown handler
+// fails.
+type ShutdownErrors []error
+
+func (errs ShutdownErrors) Error() string {
+	var strs = make([]string, len(errs))
+	for i, err := range errs {
+		strs[i] = err.Error()
+	}
+
+	return strings.Join(strs, "; ")
+}
+
+// joinErrors returns nil if errs is empty, the only error if there's only one,
+// or ShutdownErrors otherwise.
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return ShutdownErrors(errs)
+	}
+}
@@ -0,0 +1,44 @@
+package bot
+
+import "strings"
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"`", "\\`",
+	"|", `\|`,
+)
+
+// EscapeMarkdown escapes the Markdown in s, so it's shown as it is instead of
+// being formatted. This includes bold, italics, underlines, strikethroughs,
+// code blocks, spoilers, and quotes and headings at the start of lines.
+func EscapeMarkdown(s string) string {
+	var lines = strings.Split(markdownEscaper.Replace(s), "\n")
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, ">") || strings.HasPrefix(line, "#") {
+			lines[i] = `\` + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// EscapeMentions breaks all mentions in s by putting a zero-width space after
+// every @, which turns @everyone into @\u200beveryone and <@id> into
+// <@\u200bid>. AllowedMentions already stops the pings, but this also keeps
+// the mentions from rendering.
+func EscapeMentions(s string) string {
+	return strings.Replace(s, "@", "@\u200b", -1)
+}
+
+// Escape escapes both the Markdown and the mentions in s. It can be used as a
+// SanitizeMessage for commands that echo user input:
+//
+//    sub.SanitizeMessage = bot.Escape
+//
+func Escape(s string) string {
+	return EscapeMentions(EscapeMarkdown(s))
+}
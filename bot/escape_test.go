@@ -0,0 +1,25 @@
+package bot
+
+import "testing"
+
+func TestEscape(t *testing.T) {
+	var tests = []struct {
+		in, markdown, all string
+	}{
+		{"plain", "plain", "plain"},
+		{"**bold** _it_ ~~no~~", `\*\*bold\*\* \_it\_ \~\~no\~\~`, `\*\*bold\*\* \_it\_ \~\~no\~\~`},
+		{"`code` ||spoiler||", "\\`code\\` \\|\\|spoiler\\|\\|", "\\`code\\` \\|\\|spoiler\\|\\|"},
+		{`a\b`, `a\\b`, `a\\b`},
+		{"> quote\n# heading\nnot > quote", "\\> quote\n\\# heading\nnot > quote", "\\> quote\n\\# heading\nnot > quote"},
+		{"@everyone <@1>", "@everyone <@1>", "@\u200beveryone <@\u200b1>"},
+	}
+
+	for _, test := range tests {
+		if got := EscapeMarkdown(test.in); got != test.markdown {
+			t.Errorf("EscapeMarkdown(%q) = %q, expected %q", test.in, got, test.markdown)
+		}
+		if got := Escape(test.in); got != test.all {
+			t.Errorf("Escape(%q) = %q, expected %q", test.in, got, test.all)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package bot
+
+import (
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/api"
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/pkg/errors"
+)
+
+// RegisterHelp adds a help command with the given name, usually "help", into
+// the main commands. Without arguments, the command replies with the overview
+// of all commands. Otherwise, it replies with the help of a single command or
+// subcommand, such as "help send", "help sub cmd" or "help sub nested cmd".
+// AdminOnly commands are hidden from anyone who's not an administrator.
+func (ctx *Context) RegisterHelp(name string) error {
+	for _, cmd := range ctx.Commands {
+		if cmd.isCommand(name) {
+			return errors.New("Help command has duplicate name: " + name)
+		}
+	}
+
+	arg, err := newArgument(reflect.TypeOf(""), true)
+	if err != nil {
+		return errors.Wrap(err, "Failed to make help argument")
+	}
+
+	var fn = func(mc *gateway.MessageCreateEvent, args ...string) (string, error) {
+		return ctx.helpFor(mc, args)
+	}
+
+	ctx.Commands = append(ctx.Commands, &CommandContext{
+		Description: "Show help for all commands or the given command.",
+		MethodName:  "Help",
+		Command:     name,
+		Variadic:    true,
+		value:       reflect.ValueOf(fn),
+		event:       typeMessageCreate,
+		Arguments:   []Argument{*arg},
+	})
+
+	return nil
+}
+
+func (ctx *Context) helpFor(
+	mc *gateway.MessageCreateEvent, args []string) (string, error) {
+
+	var hideAdmin = true
+	if mc.GuildID.Valid() {
+		p, err := ctx.State.Permissions(mc.ChannelID, mc.Author.ID)
+		hideAdmin = err != nil || !p.Has(discord.PermissionAdministrator)
+	}
+
+	if len(args) == 0 {
+		return ctx.help(hideAdmin), nil
+	}
+
+	if len(args) == 1 {
+		if cmd := findCommand(ctx.Commands, args[0], hideAdmin); cmd != nil {
+			return ctx.Subcommand.helpCommand("", cmd), nil
+		}
+	}
+
+	// Walk down the subcommands for as long as the words match their names.
+	var sub = ctx.Subcommand
+	var depth int
+
+	for ; depth < len(args); depth++ {
+		next := findSubcommand(sub.Subcommands(), args[depth])
+		if next == nil || (next.Flag.Is(AdminOnly) && hideAdmin) {
+			break
+		}
+		sub = next
+	}
+
+	switch {
+	case depth == 0:
+		// Not a subcommand.
+
+	case depth == len(args):
+		if help := sub.Help("", hideAdmin); help != "" {
+			return help, nil
+		}
+
+		return "", &ErrUnknownCommand{
+			Command: args[depth-1],
+			Parent:  strings.Join(args[:depth-1], " "),
+		}
+
+	default:
+		if cmd := findCommand(sub.Commands, args[depth], hideAdmin); cmd != nil {
+			return sub.helpCommand("", cmd), nil
+		}
+
+		return "", &ErrUnknownCommand{
+			Command: args[depth],
+			Parent:  strings.Join(args[:depth], " "),
+		}
+	}
+
+	return "", &ErrUnknownCommand{
+		Command: args[0],
+	}
+}
+
+func findCommand(
+	cmds []*CommandContext, name string, hideAdmin bool) *CommandContext {
+
+	for _, cmd := range cmds {
+		if cmd.isCommand(name) && !(cmd.Flag.Is(AdminOnly) && hideAdmin) {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// MessageMax is the maximum length of a message's content.
+const MessageMax = api.MaxMessageContent
+
+// HelpPages splits the output of Help into pages, each of which fits into a
+// single message. Pages are only split between lines, so commands are never
+// cut in half.
+func (ctx *Context) HelpPages() []string {
+	return splitLines(strings.Split(ctx.Help(), "\n"), MessageMax)
+}
+
+// Embed limits used for HelpEmbeds, which are counted in characters. The total
+// length of an embed is limited by api.MaxEmbedsLength.
+const (
+	embedFieldsMax     = 25
+	embedFieldNameMax  = 256
+	embedFieldValueMax = 1024
+)
+
+// HelpEmbed generates the help as an embed, with each subcommand in its own
+// field. Only the first embed is returned, so HelpEmbeds should be used for
+// large bots. Like Help, AdminOnly commands are hidden.
+func (ctx *Context) HelpEmbed() *discord.Embed {
+	return ctx.HelpEmbeds(true)[0]
+}
+
+// HelpEmbeds generates the help as embeds, with each subcommand in its own
+// field. A new embed is started whenever the current one would go over
+// Discord's limits. The returned slice always has at least one embed.
+func (ctx *Context) HelpEmbeds(hideAdmin bool) []*discord.Embed {
+	var title = "Help"
+	if ctx.Name != "" {
+		title += ": " + ctx.Name
+	}
+
+	var embed = discord.NewEmbed()
+	embed.Title = title
+	embed.Description = ctx.Description
+
+	var embeds = []*discord.Embed{embed}
+	var size = utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+
+	addField := func(name string, lines []string) {
+		for _, value := range splitLines(lines, embedFieldValueMax) {
+			var fieldSize = utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+
+			if len(embed.Fields) == embedFieldsMax || size+fieldSize > api.MaxEmbedsLength {
+				embed = discord.NewEmbed()
+				embed.Title = title
+				embeds = append(embeds, embed)
+				size = utf8.RuneCountInString(title)
+			}
+
+			embed.Fields = append(embed.Fields, discord.EmbedField{
+				Name:  name,
+				Value: value,
+			})
+			size += fieldSize
+		}
+	}
+
+	if ctx.Flag.Is(AdminOnly) && hideAdmin {
+		return embeds
+	}
+
+	if lines := ctx.Subcommand.helpLines("", hideAdmin); len(lines) > 0 {
+		addField("Commands", lines)
+	}
+
+	for _, sub := range ctx.allSubcommands() {
+		if sub.Flag.Is(AdminOnly) && hideAdmin {
+			continue
+		}
+
+		lines := sub.helpLines("", hideAdmin)
+		if len(lines) == 0 {
+			continue
+		}
+
+		var name = sub.fullCommand()
+		if sub.Description != "" {
+			name += ": " + sub.Description
+		}
+		if utf8.RuneCountInString(name) > embedFieldNameMax {
+			name = cutRunes(name, embedFieldNameMax-1) + "…"
+		}
+
+		addField(name, lines)
+	}
+
+	return embeds
+}
+
+// splitLines joins lines with new lines into chunks, each of at most max
+// characters. Lines longer than max are cut.
+func splitLines(lines []string, max int) []string {
+	var chunks []string
+	var chunk string
+	var chunkLen int
+
+	for _, line := range lines {
+		var lineLen = utf8.RuneCountInString(line)
+		if lineLen > max {
+			line = cutRunes(line, max)
+			lineLen = max
+		}
+
+		if chunk != "" && chunkLen+1+lineLen > max {
+			chunks = append(chunks, chunk)
+			chunk = ""
+			chunkLen = 0
+		}
+
+		if chunk != "" {
+			chunk += "\n"
+			chunkLen++
+		}
+		chunk += line
+		chunkLen += lineLen
+	}
+
+	if chunk != "" {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// cutRunes returns the first n characters of s.
+func cutRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/state"
+)
+
+func TestHelpEmbeds(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.MustRegisterSubcommand(&testc{})
+
+	e := c.HelpEmbed()
+	if len(e.Fields) != 2 {
+		t.Fatal("Unexpected fields:", e.Fields)
+	}
+	if e.Fields[1].Name != "testc" {
+		t.Fatal("Unexpected subcommand field name:", e.Fields[1].Name)
+	}
+	if err := e.Validate(); err != nil {
+		t.Fatal("Invalid embed:", err)
+	}
+
+	// Long names are cut between characters.
+	c.Subcommands()[0].Description = strings.Repeat("あ", 300)
+
+	e = c.HelpEmbed()
+	if name := e.Fields[1].Name; !utf8.ValidString(name) || !strings.HasSuffix(name, "あ…") {
+		t.Fatalf("Unexpected subcommand field name: %q", name)
+	}
+	if err := e.Validate(); err != nil {
+		t.Fatal("Invalid embed:", err)
+	}
+}
+
+func TestHelpPages(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	for _, cmd := range c.Commands {
+		cmd.Description = strings.Repeat("a", 500)
+	}
+
+	pages := c.HelpPages()
+	if len(pages) < 2 {
+		t.Fatal("Expected multiple pages, got", len(pages))
+	}
+
+	for _, page := range pages {
+		if len(page) > MessageMax {
+			t.Fatal("Page is too long:", len(page))
+		}
+	}
+
+	if strings.Join(pages, "\n") != c.Help() {
+		t.Fatal("Pages don't add up to the help")
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	var lines = []string{"aaaa", "bbbb", "cccc", strings.Repeat("d", 12)}
+
+	chunks := splitLines(lines, 10)
+	expect := []string{"aaaa\nbbbb", "cccc", strings.Repeat("d", 10)}
+
+	if strings.Join(chunks, "|") != strings.Join(expect, "|") {
+		t.Fatalf("Unexpected chunks: %q", chunks)
+	}
+
+	// Lengths are counted in characters, and lines are cut between them.
+	lines = []string{"ああああ", "いいいい", strings.Repeat("う", 12)}
+
+	chunks = splitLines(lines, 10)
+	expect = []string{"ああああ\nいいいい", strings.Repeat("う", 10)}
+
+	if strings.Join(chunks, "|") != strings.Join(expect, "|") {
+		t.Fatalf("Unexpected chunks: %q", chunks)
+	}
+}
@@ -0,0 +1,303 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ConfirmPrompt is the message sent by Confirm.
+	ConfirmPrompt = "Are you sure?"
+	// ConfirmYes and ConfirmNo are the reactions added by Confirm.
+	ConfirmYes = "✅"
+	ConfirmNo  = "❌"
+	// ConfirmTimeout is the longest Confirm would wait for a reaction, even if
+	// the given context has no deadline.
+	ConfirmTimeout = time.Minute
+
+	// PaginatePrev and PaginateNext are the reactions added by Paginate.
+	PaginatePrev = "◀"
+	PaginateNext = "▶"
+)
+
+// ErrTooManyInteractions is returned by the interactive helpers when the
+// Context already has MaxInteractions sessions waiting for the users.
+var ErrTooManyInteractions = errors.New("Too many interactive sessions")
+
+// ActiveInteractions returns the number of interactive sessions, such as
+// Confirm, NextMessage and Paginate, that are waiting for the users.
+func (ctx *Context) ActiveInteractions() int {
+	ctx.interactionMutex.Lock()
+	defer ctx.interactionMutex.Unlock()
+
+	return ctx.interactions
+}
+
+// startInteraction counts a new interactive session, unless there are already
+// MaxInteractions. The returned context is c, but also canceled when the
+// Context is closed. The returned function must be called once the session is
+// done.
+func (ctx *Context) startInteraction(c context.Context) (context.Context, func(), error) {
+	ctx.interactionMutex.Lock()
+	defer ctx.interactionMutex.Unlock()
+
+	if ctx.MaxInteractions > 0 && ctx.interactions >= ctx.MaxInteractions {
+		return nil, nil, ErrTooManyInteractions
+	}
+	ctx.interactions++
+
+	c, cancel := context.WithCancel(c)
+	stopped := ctx.stopContext().Done()
+
+	go func() {
+		select {
+		case <-stopped:
+			cancel()
+		case <-c.Done():
+		}
+	}()
+
+	return c, func() {
+		cancel()
+
+		ctx.interactionMutex.Lock()
+		ctx.interactions--
+		ctx.interactionMutex.Unlock()
+	}, nil
+}
+
+// Confirm sends ConfirmPrompt into the channel and waits for the user to react
+// with either ConfirmYes or ConfirmNo. Reactions from other users are ignored.
+// The prompt is deleted afterwards. If the context is canceled or
+// ConfirmTimeout is reached, false is returned along with the context's error.
+// The context is also canceled when the Context is closed.
+//
+//    func (c *Commands) Purge(m *gateway.MessageCreateEvent) (string, error) {
+//        ok, err := c.Ctx.Confirm(context.Background(), m.ChannelID, m.Author.ID)
+//        if err != nil || !ok {
+//            return "Cancelled.", nil
+//        }
+//        ...
+//    }
+//
+func (ctx *Context) Confirm(c context.Context, channelID, userID discord.Snowflake) (bool, error) {
+	c, done, err := ctx.startInteraction(c)
+	if err != nil {
+		return false, err
+	}
+	defer done()
+
+	m, err := ctx.SendMessage(channelID, ConfirmPrompt, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to send prompt")
+	}
+	defer ctx.DeleteMessage(channelID, m.ID)
+
+	c, cancel := context.WithTimeout(c, ConfirmTimeout)
+	defer cancel()
+
+	// Start listening before reacting, so a quick reaction isn't missed.
+	ch, rm := ctx.ChanFor(confirmFilter(m.ID, userID))
+	defer rm()
+
+	for _, emoji := range []string{ConfirmYes, ConfirmNo} {
+		if err := ctx.React(channelID, m.ID, emoji); err != nil {
+			return false, errors.Wrap(err, "Failed to react")
+		}
+	}
+
+	return waitConfirm(c, ch)
+}
+
+// confirmFilter matches the user's ConfirmYes and ConfirmNo reactions on the
+// message.
+func confirmFilter(messageID, userID discord.Snowflake) func(interface{}) bool {
+	return func(v interface{}) bool {
+		r, ok := v.(*gateway.MessageReactionAddEvent)
+		if !ok || r.MessageID != messageID || r.UserID != userID {
+			return false
+		}
+
+		return r.Emoji.Name == ConfirmYes || r.Emoji.Name == ConfirmNo
+	}
+}
+
+// waitConfirm blocks until a reaction arrives from ch or the context is done.
+func waitConfirm(c context.Context, ch <-chan interface{}) (bool, error) {
+	select {
+	case v := <-ch:
+		return v.(*gateway.MessageReactionAddEvent).Emoji.Name == ConfirmYes, nil
+	case <-c.Done():
+		return false, c.Err()
+	}
+}
+
+// NextMessage blocks until the user sends a message in the channel, which is
+// then returned. This is useful for commands that ask several questions in a
+// row. If the timeout is reached, context.DeadlineExceeded is returned, or
+// context.Canceled if the Context is closed first.
+func (ctx *Context) NextMessage(
+	channelID, userID discord.Snowflake,
+	timeout time.Duration) (*gateway.MessageCreateEvent, error) {
+
+	c, done, err := ctx.startInteraction(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	c, cancel := context.WithTimeout(c, timeout)
+	defer cancel()
+
+	ch, rm := ctx.ChanFor(func(v interface{}) bool {
+		mc, ok := v.(*gateway.MessageCreateEvent)
+		return ok && mc.ChannelID == channelID && mc.Author.ID == userID
+	})
+	defer rm()
+
+	select {
+	case v := <-ch:
+		return v.(*gateway.MessageCreateEvent), nil
+	case <-c.Done():
+		return nil, c.Err()
+	}
+}
+
+// Paginate sends the first page into the channel and adds the PaginatePrev and
+// PaginateNext reactions, which the user can use to flip through the pages.
+// The message is edited in place, and reactions from other users are ignored.
+// Both adding and removing a reaction flip the page, so the user doesn't need
+// to remove their reaction before using it again, and the bot doesn't need
+// MANAGE_MESSAGES.
+//
+// Paginate blocks until no page has been flipped for the timeout, then removes
+// the reactions and returns nil. The message itself is kept. If the Context is
+// closed first, context.Canceled is returned instead.
+//
+//    func (c *Commands) List(m *gateway.MessageCreateEvent) error {
+//        return c.Ctx.Paginate(m.ChannelID, m.Author.ID, pages, time.Minute)
+//    }
+//
+func (ctx *Context) Paginate(
+	channelID, userID discord.Snowflake,
+	pages []*discord.Embed, timeout time.Duration) error {
+
+	if len(pages) == 0 {
+		return errors.New("No pages to paginate")
+	}
+
+	c, done, err := ctx.startInteraction(context.Background())
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	m, err := ctx.SendMessage(channelID, "", pages[0])
+	if err != nil {
+		return errors.Wrap(err, "Failed to send the first page")
+	}
+
+	// There's nothing to flip to.
+	if len(pages) == 1 {
+		return nil
+	}
+
+	ch, rm := ctx.ChanFor(paginateFilter(m.ID, userID))
+	defer rm()
+
+	var controls = []string{PaginatePrev, PaginateNext}
+
+	if err := ctx.ReactAll(channelID, m.ID, controls); err != nil {
+		return errors.Wrap(err, "Failed to add controls")
+	}
+
+	// Removing everyone's reactions needs MANAGE_MESSAGES, so at least remove
+	// our own if that fails.
+	defer func() {
+		if err := ctx.DeleteAllReactions(channelID, m.ID); err != nil {
+			ctx.UnreactAll(channelID, m.ID, controls)
+		}
+	}()
+
+	var page int
+	var timer = time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case v := <-ch:
+			next := turnPage(page, len(pages), reactionEmoji(v))
+			if next == page {
+				continue
+			}
+			page = next
+
+			if _, err := ctx.EditMessage(channelID, m.ID, "", pages[page], false); err != nil {
+				return errors.Wrap(err, "Failed to flip the page")
+			}
+
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(timeout)
+
+		case <-timer.C:
+			return nil
+
+		case <-c.Done():
+			return c.Err()
+		}
+	}
+}
+
+// paginateFilter matches the user's PaginatePrev and PaginateNext reactions
+// being added to or removed from the message.
+func paginateFilter(messageID, userID discord.Snowflake) func(interface{}) bool {
+	return func(v interface{}) bool {
+		var msgID, usrID discord.Snowflake
+
+		switch r := v.(type) {
+		case *gateway.MessageReactionAddEvent:
+			msgID, usrID = r.MessageID, r.UserID
+		case *gateway.MessageReactionRemoveEvent:
+			msgID, usrID = r.MessageID, r.UserID
+		default:
+			return false
+		}
+
+		if msgID != messageID || usrID != userID {
+			return false
+		}
+
+		emoji := reactionEmoji(v)
+		return emoji == PaginatePrev || emoji == PaginateNext
+	}
+}
+
+// reactionEmoji returns the emoji name of a reaction add or remove event.
+func reactionEmoji(v interface{}) string {
+	switch r := v.(type) {
+	case *gateway.MessageReactionAddEvent:
+		return r.Emoji.Name
+	case *gateway.MessageReactionRemoveEvent:
+		return r.Emoji.Name
+	}
+	return ""
+}
+
+// turnPage returns the page after flipping from page with the emoji. The page
+// stays the same at either end.
+func turnPage(page, pages int, emoji string) int {
+	switch {
+	case emoji == PaginatePrev && page > 0:
+		return page - 1
+	case emoji == PaginateNext && page < pages-1:
+		return page + 1
+	default:
+		return page
+	}
+}
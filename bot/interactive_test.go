@@ -0,0 +1,192 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/diamondburned/arikawa/handler"
+	"github.com/diamondburned/arikawa/state"
+)
+
+func TestWaitConfirm(t *testing.T) {
+	var h = handler.New()
+
+	ch, rm := h.ChanFor(confirmFilter(1, 2))
+	defer rm()
+
+	go func() {
+		// Wrong message, wrong user and wrong emoji.
+		h.Call(&gateway.MessageReactionAddEvent{MessageID: 3, UserID: 2, Emoji: discord.Emoji{Name: ConfirmYes}})
+		h.Call(&gateway.MessageReactionAddEvent{MessageID: 1, UserID: 3, Emoji: discord.Emoji{Name: ConfirmYes}})
+		h.Call(&gateway.MessageReactionAddEvent{MessageID: 1, UserID: 2, Emoji: discord.Emoji{Name: "👍"}})
+
+		h.Call(&gateway.MessageReactionAddEvent{MessageID: 1, UserID: 2, Emoji: discord.Emoji{Name: ConfirmNo}})
+	}()
+
+	ok, err := waitConfirm(context.Background(), ch)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if ok {
+		t.Fatal("Expected the confirmation to be declined")
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	if _, err := waitConfirm(c, ch); err != context.DeadlineExceeded {
+		t.Fatal("Unexpected error:", err)
+	}
+}
+
+func TestNextMessage(t *testing.T) {
+	var state = &state.State{
+		Store:   state.NewDefaultStore(nil),
+		Handler: handler.New(),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	newMessage := func(channelID, userID discord.Snowflake, content string) *gateway.MessageCreateEvent {
+		return &gateway.MessageCreateEvent{
+			Message: discord.Message{
+				ChannelID: channelID,
+				Author:    discord.User{ID: userID},
+				Content:   content,
+			},
+		}
+	}
+
+	go func() {
+		// Give NextMessage some time to subscribe.
+		time.Sleep(10 * time.Millisecond)
+
+		state.Call(newMessage(1, 3, "wrong user"))
+		state.Call(newMessage(3, 2, "wrong channel"))
+		state.Call(newMessage(1, 2, "title"))
+	}()
+
+	mc, err := c.NextMessage(1, 2, time.Second)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if mc.Content != "title" {
+		t.Fatal("Unexpected message:", mc.Content)
+	}
+
+	if _, err := c.NextMessage(1, 2, time.Millisecond); err != context.DeadlineExceeded {
+		t.Fatal("Unexpected error:", err)
+	}
+}
+
+func TestInteractionLimit(t *testing.T) {
+	var state = &state.State{
+		Store:   state.NewDefaultStore(nil),
+		Handler: handler.New(),
+	}
+
+	c, err := New(state, &testc{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.MaxInteractions = 1
+
+	var errs = make(chan error)
+	go func() {
+		_, err := c.NextMessage(1, 2, time.Minute)
+		errs <- err
+	}()
+
+	for c.ActiveInteractions() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := c.NextMessage(1, 2, time.Minute); err != ErrTooManyInteractions {
+		t.Fatal("Unexpected error over the limit:", err)
+	}
+
+	// Closing the Context stops the waiting session.
+	if err := c.Close(); err != nil {
+		t.Fatal("Failed to close:", err)
+	}
+
+	if err := <-errs; err != context.Canceled {
+		t.Fatal("Unexpected error after closing:", err)
+	}
+	if n := c.ActiveInteractions(); n != 0 {
+		t.Fatal("Unexpected active interactions:", n)
+	}
+}
+
+func TestInteractionNotStarted(t *testing.T) {
+	// A Context not made with New has no stop context.
+	var c Context
+
+	ictx, done, err := c.startInteraction(context.Background())
+	if err != nil {
+		t.Fatal("Failed to start interaction:", err)
+	}
+
+	// The session keeps going until it's done.
+	time.Sleep(10 * time.Millisecond)
+	if err := ictx.Err(); err != nil {
+		t.Fatal("Unexpected error before done:", err)
+	}
+
+	done()
+
+	select {
+	case <-ictx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Interaction wasn't canceled")
+	}
+}
+
+func TestPaginateFilter(t *testing.T) {
+	var filter = paginateFilter(1, 2)
+
+	var tests = []struct {
+		ev    interface{}
+		match bool
+	}{
+		{&gateway.MessageReactionAddEvent{MessageID: 1, UserID: 2, Emoji: discord.Emoji{Name: PaginateNext}}, true},
+		{&gateway.MessageReactionRemoveEvent{MessageID: 1, UserID: 2, Emoji: discord.Emoji{Name: PaginatePrev}}, true},
+		{&gateway.MessageReactionAddEvent{MessageID: 3, UserID: 2, Emoji: discord.Emoji{Name: PaginateNext}}, false},
+		{&gateway.MessageReactionAddEvent{MessageID: 1, UserID: 3, Emoji: discord.Emoji{Name: PaginateNext}}, false},
+		{&gateway.MessageReactionAddEvent{MessageID: 1, UserID: 2, Emoji: discord.Emoji{Name: "👍"}}, false},
+		{&gateway.MessageCreateEvent{}, false},
+	}
+
+	for i, test := range tests {
+		if filter(test.ev) != test.match {
+			t.Errorf("Unexpected match for event %d", i)
+		}
+	}
+}
+
+func TestTurnPage(t *testing.T) {
+	var tests = []struct {
+		page  int
+		emoji string
+		next  int
+	}{
+		{0, PaginateNext, 1},
+		{1, PaginatePrev, 0},
+		{0, PaginatePrev, 0},
+		{2, PaginateNext, 2},
+		{1, "👍", 1},
+	}
+
+	for _, test := range tests {
+		if next := turnPage(test.page, 3, test.emoji); next != test.next {
+			t.Errorf("Turning page %d with %s gave %d, expected %d",
+				test.page, test.emoji, next, test.next)
+		}
+	}
+}
@@ -26,13 +26,20 @@ const Raw NameFlag = 1 << 1
 const AdminOnly NameFlag = 1 << 2
 
 // G - GuildOnly, which tells the library to only run the Subcommand/method
-// if the user is inside a guild.
+// if the user is inside a guild. Commands used outside of guilds are replied
+// with ErrGuildOnly.
 const GuildOnly NameFlag = 1 << 3
 
 // M - Middleware, which tells the library that the method is a middleware.
 // The method will be executed anytime a method of the same struct is
 // matched.
 //
+// Middlewares can abort the execution by returning a non-nil error. The
+// matched method will then not be called, and the error goes through the
+// usual error path, that is, it is either replied or logged. Returning Break
+// aborts silently. Middlewares of the same struct are called in the order of
+// their method names, and the first one to return an error stops the rest.
+//
 // Using this flag inside the subcommand will drop all methods (this is an
 // undefined behavior/UB).
 const Middleware NameFlag = 1 << 4
@@ -40,6 +47,8 @@ const Middleware NameFlag = 1 << 4
 // H - Hidden/Handler, which tells the router to not add this into the list
 // of commands, hiding it from Help. Handlers that are hidden will not have
 // any arguments parsed. It will be treated as an Event.
+//
+// If set with SetFlag instead, the command is only hidden from Help.
 const Hidden NameFlag = 1 << 5
 
 // P - Plumb, which tells the router to call only this handler with all the
@@ -74,6 +83,11 @@ const Hidden NameFlag = 1 << 5
 //
 const Plumb NameFlag = 1 << 6
 
+// D - DMOnly, which tells the library to only run the Subcommand/method if the
+// user is in a direct message. This is the opposite of GuildOnly. Commands used
+// in guilds are replied with ErrDMOnly.
+const DMOnly NameFlag = 1 << 7
+
 func ParseFlag(name string) (NameFlag, string) {
 	parts := strings.SplitN(name, string(FlagSeparator), 2)
 	if len(parts) != 2 {
@@ -96,6 +110,8 @@ func ParseFlag(name string) (NameFlag, string) {
 			f |= Hidden
 		case 'P':
 			f |= Plumb
+		case 'D':
+			f |= DMOnly
 		}
 	}
 
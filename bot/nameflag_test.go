@@ -15,6 +15,9 @@ func TestNameFlag(t *testing.T) {
 	}, {
 		Name:   "RAーGC",
 		Expect: Raw | AdminOnly,
+	}, {
+		Name:   "DーLink",
+		Expect: DMOnly,
 	}}
 
 	for _, entry := range entries {
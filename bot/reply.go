@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"io"
+
+	"github.com/diamondburned/arikawa/api"
+	"github.com/diamondburned/arikawa/discord"
+)
+
+// FileReply is a reply with files attached. It can be returned from a command
+// to upload the files, optionally alongside a content and an embed:
+//
+//    func (c *Commands) Export(m *gateway.MessageCreateEvent) (*bot.FileReply, error) {
+//        return bot.NewFileReply("export.txt", strings.NewReader(data)), nil
+//    }
+//
+type FileReply struct {
+	Content string
+	Embed   *discord.Embed
+	Files   []api.SendMessageFile
+}
+
+// NewFileReply creates a reply with a single file.
+func NewFileReply(name string, r io.Reader) *FileReply {
+	return &FileReply{
+		Files: []api.SendMessageFile{{Name: name, Reader: r}},
+	}
+}
+
+// AddFile adds a file into the reply. It returns the same reply for chaining.
+func (r *FileReply) AddFile(name string, reader io.Reader) *FileReply {
+	r.Files = append(r.Files, api.SendMessageFile{Name: name, Reader: reader})
+	return r
+}
+
+// Responder is a message sent by the bot that can later be edited or deleted,
+// such as a status message that's updated as a command makes progress.
+type Responder struct {
+	// Message is the sent message. It's replaced with the edited message after
+	// each Edit.
+	Message *discord.Message
+
+	ctx *Context
+}
+
+// SendAndTrack sends the message and returns a Responder to edit or delete it
+// with. The Context's AllowedMentions is used if data doesn't set its own:
+//
+//    r, err := ctx.SendAndTrack(m.ChannelID, api.SendMessageData{
+//        Content: "Downloading...",
+//    })
+//    if err != nil {
+//        return err
+//    }
+//
+//    download()
+//    return r.Edit("Done!", nil)
+//
+func (ctx *Context) SendAndTrack(
+	channelID discord.Snowflake, data api.SendMessageData) (*Responder, error) {
+
+	if data.AllowedMentions == nil {
+		data.AllowedMentions = ctx.AllowedMentions
+	}
+
+	m, err := ctx.SendMessageComplex(channelID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Responder{Message: m, ctx: ctx}, nil
+}
+
+// Edit replaces the content and embed of the message. An empty content or a
+// nil embed leaves that part unchanged.
+func (r *Responder) Edit(content string, embed *discord.Embed) error {
+	m, err := r.ctx.EditMessage(r.Message.ChannelID, r.Message.ID, content, embed, false)
+	if err != nil {
+		return err
+	}
+
+	r.Message = m
+	return nil
+}
+
+// Delete deletes the message.
+func (r *Responder) Delete() error {
+	return r.ctx.DeleteMessage(r.Message.ChannelID, r.Message.ID)
+}
@@ -78,11 +78,11 @@ func Parse(line string) ([]string, error) {
 	}
 
 	if escaped || singleQuoted || doubleQuoted {
-		// the number of characters to highlight
+		// the number of characters to highlight, bounded for short lines
 		var (
-			pos   = cursor + 5
-			start = string(runes[max(cursor-100, 0) : pos-1])
-			end   = string(runes[pos+1 : min(cursor+100, len(runes))])
+			pos   = min(cursor+5, len(runes))
+			start = string(runes[max(cursor-100, 0):max(pos-1, 0)])
+			end   = string(runes[min(pos+1, len(runes)):min(cursor+100, len(runes))])
 			part  = ""
 		)
 
@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"github.com/diamondburned/arikawa/api"
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+)
+
+// Simulate runs the content through the command router as if it was sent in
+// a message, then returns the replies that would have been sent, along with
+// the error that the command returned. Refer to SimulateMessage.
+//
+//    replies, err := ctx.Simulate("~ping")
+//    if err != nil || replies[0].Content != "Pong!" {
+//        t.Fatal("Unexpected reply:", replies, err)
+//    }
+//
+func (ctx *Context) Simulate(content string) ([]api.SendMessageData, error) {
+	return ctx.SimulateMessage(&gateway.MessageCreateEvent{
+		Message: discord.Message{Content: content},
+	})
+}
+
+// SimulateMessage handles the message like the router handles a Message Create
+// event, but the replies are returned instead of being sent. This includes the
+// error reply if ReplyError is true. The returned error is the one that the
+// command returned, if any. The message's Author, GuildID and Member could be
+// set to invoke the command as someone or in a guild.
+//
+// Typing indicators and the clean up of DeleteReplyAfter and DeleteInvocation
+// are skipped. Anything else that the command or the router does through the
+// State still goes through as usual, so the Store should have the guild and
+// member for guild or permission checks to not hit the network.
+func (ctx *Context) SimulateMessage(
+	mc *gateway.MessageCreateEvent) ([]api.SendMessageData, error) {
+
+	var replies []api.SendMessageData
+
+	ctx.simulations.Store(mc, &replies)
+	defer ctx.simulations.Delete(mc)
+
+	err := ctx.callCmd(mc)
+	ctx.handleError(mc, err)
+
+	return replies, err
+}
+
+// isSimulated returns whether the message was given to SimulateMessage.
+func (ctx *Context) isSimulated(mc *gateway.MessageCreateEvent) bool {
+	_, ok := ctx.simulations.Load(mc)
+	return ok
+}
@@ -0,0 +1,155 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/api"
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/diamondburned/arikawa/state"
+)
+
+type testSimulate struct {
+	Ctx *Context
+}
+
+func (t *testSimulate) Setup(sub *Subcommand) {
+	// Neither of these should hit the network.
+	sub.FindCommand("Greet").ShowTyping = true
+	sub.FindCommand("Greet").DeleteReplyAfter = 1
+}
+
+func (t *testSimulate) Greet(m *gateway.MessageCreateEvent) (string, error) {
+	return "Hello, " + m.Author.Username + "!", nil
+}
+
+func (t *testSimulate) Long(_ *gateway.MessageCreateEvent) (string, error) {
+	return strings.Repeat("a ", MessageMax), nil
+}
+
+func (t *testSimulate) GーGuild(m *gateway.MessageCreateEvent) (string, error) {
+	return m.GuildID.String(), nil
+}
+
+func (t *testSimulate) Embeds(_ *gateway.MessageCreateEvent) ([]*discord.Embed, error) {
+	var embed = &discord.Embed{Description: strings.Repeat("a", 2048)}
+	return []*discord.Embed{embed, embed, embed}, nil
+}
+
+func (t *testSimulate) Fail(_ *gateway.MessageCreateEvent) error {
+	return errors.New("failed")
+}
+
+func TestSimulate(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testSimulate{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+	c.ReplyError = true
+
+	replies, err := c.SimulateMessage(&gateway.MessageCreateEvent{
+		Message: discord.Message{
+			Content: "!greet",
+			Author:  discord.User{Username: "user"},
+		},
+	})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if len(replies) != 1 || replies[0].Content != "Hello, user!" {
+		t.Fatal("Unexpected replies:", replies)
+	}
+
+	if replies, _ := c.Simulate("!long"); len(replies) != 2 {
+		t.Fatal("Unexpected reply count:", len(replies))
+	}
+
+	replies, err = c.Simulate("!fail")
+	if err == nil || err.Error() != "failed" {
+		t.Fatal("Unexpected error:", err)
+	}
+	if len(replies) != 1 || replies[0].Content != "failed" {
+		t.Fatal("Unexpected error replies:", replies)
+	}
+
+	// The embeds are fine individually, but not combined.
+	replies, err = c.Simulate("!embeds")
+	if err == nil || !strings.HasPrefix(err.Error(), "Invalid reply: Embeds overbound") {
+		t.Fatal("Unexpected error for long embeds:", err)
+	}
+	if len(replies) != 1 || replies[0].Content != err.Error() {
+		t.Fatal("Unexpected replies for long embeds:", replies)
+	}
+
+	if _, err := c.Simulate("!guild"); !errors.As(err, new(*ErrGuildOnly)) {
+		t.Fatal("Unexpected error outside of a guild:", err)
+	}
+
+	replies, err = c.SimulateMessage(&gateway.MessageCreateEvent{
+		Message: discord.Message{Content: "!guild", GuildID: 1},
+	})
+	if err != nil || len(replies) != 1 || replies[0].Content != "1" {
+		t.Fatal("Unexpected replies in a guild:", replies, err)
+	}
+
+	if replies, _ := c.Simulate("hello"); len(replies) != 0 {
+		t.Fatal("Unexpected replies without a prefix:", replies)
+	}
+}
+
+func TestErrorReply(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testSimulate{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	var logged []error
+	c.ErrorLogger = func(err error) { logged = append(logged, err) }
+
+	c.ErrorReply = func(err error) (*api.SendMessageData, bool) {
+		var unknown *ErrUnknownCommand
+		if errors.As(err, &unknown) {
+			return &api.SendMessageData{Content: "No " + unknown.Command}, true
+		}
+		return nil, false
+	}
+
+	replies, err := c.Simulate("!nope")
+	if !errors.As(err, new(*ErrUnknownCommand)) {
+		t.Fatal("Unexpected error:", err)
+	}
+	if len(replies) != 1 || replies[0].Content != "No nope" {
+		t.Fatal("Unexpected replies:", replies)
+	}
+	if replies[0].AllowedMentions != c.AllowedMentions {
+		t.Fatal("Reply doesn't use the default AllowedMentions")
+	}
+
+	// Hidden errors are logged instead.
+	replies, err = c.Simulate("!fail")
+	if err == nil || len(replies) != 0 {
+		t.Fatal("Unexpected replies to hidden error:", replies, err)
+	}
+	if len(logged) != 1 || logged[0].Error() != "Command error: failed" {
+		t.Fatal("Unexpected logged errors:", logged)
+	}
+
+	// ErrorReply is only used if replies are enabled.
+	c.ReplyError = false
+
+	if replies, _ := c.Simulate("!nope"); len(replies) != 0 {
+		t.Fatal("Unexpected replies with ReplyError disabled:", replies)
+	}
+}
@@ -0,0 +1,131 @@
+package bot
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/api"
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+)
+
+const codeFence = "```"
+
+// SplitMessage splits the content into chunks of at most limit bytes, so each
+// could be sent as its own message. Content is split on new lines if possible,
+// then on spaces, and only then in the middle of a word. If a chunk ends inside
+// a code block, the block is closed and reopened with the same language in the
+// next chunk. The limit should be much larger than a code fence, such as
+// MessageMax.
+func SplitMessage(content string, limit int) []string {
+	if len(content) <= limit {
+		return []string{content}
+	}
+
+	var chunks []string
+	var inCode bool
+	var lang string
+
+	for content != "" {
+		var prefix string
+		if inCode {
+			prefix = codeFence + lang + "\n"
+		}
+
+		if len(prefix)+len(content) <= limit {
+			chunks = append(chunks, prefix+content)
+			break
+		}
+
+		var max = limit - len(prefix)
+		var cut, skip = splitIndex(content, max)
+		var open, openLang = scanFences(content[:cut], inCode, lang)
+
+		// Cut again if there's no room to close the code block.
+		if open {
+			cut, skip = splitIndex(content, max-len("\n"+codeFence))
+			open, openLang = scanFences(content[:cut], inCode, lang)
+		}
+
+		var chunk = prefix + content[:cut]
+		if open {
+			chunk += "\n" + codeFence
+		}
+
+		chunks = append(chunks, chunk)
+		content = content[cut+skip:]
+		inCode, lang = open, openLang
+	}
+
+	return chunks
+}
+
+// splitIndex returns where to cut s so the first part is at most max bytes,
+// and how many separator bytes after it should be dropped.
+func splitIndex(s string, max int) (cut, skip int) {
+	if max < 1 {
+		max = 1
+	}
+
+	if i := strings.LastIndexByte(s[:max+1], '\n'); i > 0 {
+		return i, 1
+	}
+	if i := strings.LastIndexByte(s[:max+1], ' '); i > 0 {
+		return i, 1
+	}
+
+	// Don't cut a rune in half.
+	for cut = max; cut > 0 && !utf8.RuneStart(s[cut]); cut-- {
+	}
+	if cut == 0 {
+		cut = max
+	}
+
+	return cut, 0
+}
+
+// scanFences returns whether the code block is still open after s, given
+// whether it was open before s, along with the language of the open block.
+func scanFences(s string, inCode bool, lang string) (bool, string) {
+	for _, line := range strings.Split(s, "\n") {
+		var n = strings.Count(line, codeFence)
+		if n%2 == 0 {
+			continue
+		}
+
+		inCode = !inCode
+
+		if inCode {
+			lang = ""
+			// A language is only given when the fence starts the line.
+			if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, codeFence) && n == 1 {
+				lang = strings.TrimSpace(trimmed[len(codeFence):])
+			}
+		}
+	}
+
+	return inCode, lang
+}
+
+// sendContent sends the data to the message's channel, with its content split
+// into several messages if it goes over MessageMax. Only the first message
+// keeps the data's Reference. The messages sent before any error are returned.
+func (ctx *Context) sendContent(
+	mc *gateway.MessageCreateEvent, data api.SendMessageData) ([]*discord.Message, error) {
+
+	var sent []*discord.Message
+
+	for _, chunk := range SplitMessage(data.Content, MessageMax) {
+		data.Content = chunk
+
+		m, err := ctx.sendMessage(mc, data)
+		if err != nil {
+			return sent, err
+		}
+
+		sent = append(sent, m)
+		data.Reference = nil
+	}
+
+	return sent, nil
+}
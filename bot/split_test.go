@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitMessage(t *testing.T) {
+	var tests = []struct {
+		name    string
+		content string
+		limit   int
+		expects []string
+	}{{
+		name:    "short",
+		content: "hello world",
+		limit:   20,
+		expects: []string{"hello world"},
+	}, {
+		name:    "lines",
+		content: "first line\nsecond line\nthird line",
+		limit:   24,
+		expects: []string{"first line\nsecond line", "third line"},
+	}, {
+		name:    "words",
+		content: "the quick brown fox jumps",
+		limit:   16,
+		expects: []string{"the quick brown", "fox jumps"},
+	}, {
+		name:    "long word",
+		content: strings.Repeat("a", 25),
+		limit:   10,
+		expects: []string{"aaaaaaaaaa", "aaaaaaaaaa", "aaaaa"},
+	}, {
+		name:    "code block",
+		content: "```go\nfoo()\nbar()\nbaz()\n```",
+		limit:   22,
+		expects: []string{"```go\nfoo()\nbar()\n```", "```go\nbaz()\n```"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chunks := SplitMessage(test.content, test.limit)
+			if !reflect.DeepEqual(chunks, test.expects) {
+				t.Fatalf("Unexpected chunks: %q", chunks)
+			}
+
+			for _, chunk := range chunks {
+				if len(chunk) > test.limit {
+					t.Fatalf("Chunk %q is over the limit", chunk)
+				}
+			}
+		})
+	}
+}
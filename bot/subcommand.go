@@ -1,8 +1,12 @@
 package bot
 
 import (
+	"context"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/diamondburned/arikawa/api"
 	"github.com/diamondburned/arikawa/discord"
@@ -15,15 +19,30 @@ var (
 
 	typeString = reflect.TypeOf("")
 	typeEmbed  = reflect.TypeOf((*discord.Embed)(nil))
+	typeEmbeds = reflect.TypeOf(([]*discord.Embed)(nil))
 	typeSend   = reflect.TypeOf((*api.SendMessageData)(nil))
+	typeFile   = reflect.TypeOf((*FileReply)(nil))
 
 	typeSubcmd = reflect.TypeOf((*Subcommand)(nil))
 
+	typeDuration  = reflect.TypeOf(time.Duration(0))
+	typeSnowflake = reflect.TypeOf(discord.Snowflake(0))
+	typeUser      = reflect.TypeOf((*discord.User)(nil))
+	typeChannel   = reflect.TypeOf((*discord.Channel)(nil))
+	typeEmoji     = reflect.TypeOf(discord.Emoji{})
+	typeRemainder = reflect.TypeOf(RawRemainder(""))
+	typeContent   = reflect.TypeOf(RawContent(""))
+
+	typeIContext = reflect.TypeOf((*context.Context)(nil)).Elem()
+
 	typeIError  = reflect.TypeOf((*error)(nil)).Elem()
 	typeIManP   = reflect.TypeOf((*ManualParser)(nil)).Elem()
 	typeICusP   = reflect.TypeOf((*CustomParser)(nil)).Elem()
 	typeIParser = reflect.TypeOf((*Parser)(nil)).Elem()
 	typeIUsager = reflect.TypeOf((*Usager)(nil)).Elem()
+
+	typeArguments = reflect.TypeOf(Arguments(nil))
+
 	typeSetupFn = func() reflect.Type {
 		method, _ := reflect.TypeOf((*CanSetup)(nil)).
 			Elem().
@@ -49,11 +68,15 @@ func underline(word string) string {
 // Allowed method signatures
 //
 // These are the acceptable function signatures that would be parsed as commands
-// or events. A return type <T> implies that return value will be ignored.
+// or events. A return type <T> that isn't one of the types above is only
+// allowed if ReplyJSON is true.
 //
 //    func(*gateway.MessageCreateEvent, ...) (string, error)
+//    func(*gateway.MessageCreateEvent, ...) (string, *discord.Embed, error)
 //    func(*gateway.MessageCreateEvent, ...) (*discord.Embed, error)
+//    func(*gateway.MessageCreateEvent, ...) ([]*discord.Embed, error)
 //    func(*gateway.MessageCreateEvent, ...) (*api.SendMessageData, error)
+//    func(*gateway.MessageCreateEvent, ...) (*bot.FileReply, error)
 //    func(*gateway.MessageCreateEvent, ...) (T, error)
 //    func(*gateway.MessageCreateEvent, ...) error
 //    func(*gateway.MessageCreateEvent, ...)
@@ -61,6 +84,9 @@ func underline(word string) string {
 //    func(<AnyEvent>) error
 //    func(<AnyEvent>)
 //
+// Any of the above signatures may also take a context.Context as the first
+// argument. The context is cancelled once the Context is stopped.
+//
 type Subcommand struct {
 	Description string
 
@@ -82,8 +108,27 @@ type Subcommand struct {
 	// all other subcommands.
 	QuietUnknownCommand bool
 
-	// Commands can actually return either a string, an embed, or a
-	// SendMessageData, with error as the second argument.
+	// ReplyJSON, if true, will make the bot reply with the return value of any
+	// command returning a type that isn't a known reply type, marshaled into
+	// a JSON code block. By default, methods returning such types aren't
+	// commands. It has to be set before InitCommands finishes, such as in
+	// Setup.
+	ReplyJSON bool
+
+	// ReplyToUser, if true, will make the bot's replies to commands reference
+	// the message that invoked the command, so they're shown as replies. If
+	// this is set in Context, it will apply to all other subcommands.
+	ReplyToUser bool
+
+	// ShowTyping, if true, will make the bot show the typing indicator while
+	// any of the subcommand's commands are running. Refer to
+	// CommandContext's ShowTyping for a per-command option.
+	ShowTyping bool
+
+	// Commands can actually return either a string, an embed, a slice of
+	// embeds, a SendMessageData, or a FileReply, with error as the second
+	// argument. They can also return a string and an embed, followed by the
+	// error, to reply with both.
 
 	// All registered command contexts:
 	Commands []*CommandContext
@@ -92,6 +137,16 @@ type Subcommand struct {
 	// Middleware command contexts:
 	mwMethods []*CommandContext
 
+	// Nested subcommands, added with RegisterSubcommand. This is not exported,
+	// as it shouldn't be used directly; use Subcommands instead. The slice is
+	// never modified in place, so a copy of it can be read without the mutex.
+	subcommands []*Subcommand
+	subMutex    sync.RWMutex
+	// The subcommand that this one is nested in, or nil for Context.
+	parent *Subcommand
+	// The Context given to InitCommands.
+	ctx *Context
+
 	// Plumb nameflag, use Commands[0] if true.
 	plumb bool
 
@@ -117,6 +172,10 @@ type CommandContext struct {
 	MethodName string
 	Command    string // empty if Plumb
 
+	// Aliases are alternative names that the command can be invoked with.
+	// Refer to (*Subcommand).AddAliases.
+	Aliases []string
+
 	// Hidden is true if the method has a hidden nameflag.
 	Hidden bool
 
@@ -124,9 +183,54 @@ type CommandContext struct {
 	// argument accepts multiple strings.
 	Variadic bool
 
-	value  reflect.Value // Func
-	event  reflect.Type  // gateway.*Event
-	method reflect.Method
+	// Permissions, if not zero, are the permissions that the invoking member
+	// must have in the channel for the command to run. This check is skipped
+	// in direct messages.
+	Permissions discord.Permissions
+
+	// ShowTyping, if true, will make the bot show the typing indicator in the
+	// channel until the method returns.
+	ShowTyping bool
+
+	// Cooldown, if not nil, limits how often the command can be invoked. Refer
+	// to NewCooldown.
+	Cooldown *Cooldown
+
+	// Delimiter, if not empty, is what the arguments are split on instead of
+	// spaces, and the spaces around each argument are trimmed. Quotes are kept
+	// as they are. This is useful for lists, such as with a comma:
+	//
+	//    // ~poll Best color?, red, green, blue
+	//    sub.FindCommand("Poll").Delimiter = ","
+	//
+	Delimiter string
+
+	// DeleteReplyAfter, if not zero, deletes the command's reply after the
+	// duration, to keep busy channels clean.
+	DeleteReplyAfter time.Duration
+
+	// DeleteInvocation, if true, also deletes the message that invoked the
+	// command, along with the reply if DeleteReplyAfter is set. It's only
+	// deleted in guild channels where the bot has the Manage Messages
+	// permission.
+	DeleteInvocation bool
+
+	// variadicMin and variadicMax bound the number of variadic arguments.
+	// Refer to SetVariadicBounds.
+	variadicMin int
+	variadicMax int
+
+	value reflect.Value // Func
+	event reflect.Type  // gateway.*Event
+
+	// withContext is true if the method takes a context.Context before the
+	// event.
+	withContext bool
+	// withArguments is true if the method takes Arguments after the event.
+	withArguments bool
+	// unknownReply is true if the method returns data that's only replied with
+	// ReplyJSON.
+	unknownReply bool
 
 	Arguments []Argument
 }
@@ -139,6 +243,34 @@ type CanSetup interface {
 	Setup(*Subcommand)
 }
 
+// takesRaw returns true if the command's last argument is parsed from the raw
+// string, such as RawContent and RawRemainder, or if the command splits its
+// arguments with its own Delimiter. These commands don't need the content to
+// be valid shellwords.
+func (cctx *CommandContext) takesRaw() bool {
+	if cctx.Delimiter != "" {
+		return true
+	}
+	if len(cctx.Arguments) == 0 {
+		return false
+	}
+	return cctx.Arguments[len(cctx.Arguments)-1].custom != nil
+}
+
+// isCommand returns true if name matches the command's name or one of its
+// aliases.
+func (cctx *CommandContext) isCommand(name string) bool {
+	if cctx.Command == name {
+		return true
+	}
+	for _, alias := range cctx.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (cctx *CommandContext) Usage() []string {
 	if len(cctx.Arguments) == 0 {
 		return nil
@@ -146,14 +278,143 @@ func (cctx *CommandContext) Usage() []string {
 
 	var arguments = make([]string, len(cctx.Arguments))
 	for i, arg := range cctx.Arguments {
-		arguments[i] = arg.String
+		if arg.Optional {
+			arguments[i] = "[" + arg.String + "]"
+		} else {
+			arguments[i] = arg.String
+		}
+
+		// Show where the arguments are split.
+		if cctx.Delimiter != "" && i < len(cctx.Arguments)-1 {
+			arguments[i] += cctx.Delimiter
+		}
+	}
+
+	// Show the bounds after the variadic argument.
+	if cctx.bounded() {
+		arguments[len(arguments)-1] += "... (" + cctx.boundsString() + ")"
 	}
 
 	return arguments
 }
 
+// SetVariadicBounds limits how many arguments the variadic parameter of the
+// command takes. A max of 0 means no limit. Commands given too few or too many
+// arguments fail with ErrInvalidUsage before the method is called. Only
+// commands with a variadic parameter, such as ...string, can have bounds.
+//
+//    // func (c *Commands) Pick(m *gateway.MessageCreateEvent, items ...string)
+//    err := sub.FindCommand("Pick").SetVariadicBounds(1, 5)
+//
+func (cctx *CommandContext) SetVariadicBounds(min, max int) error {
+	if n := len(cctx.Arguments); !cctx.Variadic || n == 0 || cctx.Arguments[n-1].fn == nil {
+		return errors.New("Only commands with a variadic parameter can have bounds")
+	}
+
+	if min < 0 || max < 0 || (max > 0 && min > max) {
+		return errors.Errorf("Invalid bounds: %d to %d", min, max)
+	}
+
+	cctx.variadicMin = min
+	cctx.variadicMax = max
+	return nil
+}
+
+// bounded returns true if the variadic arguments have bounds.
+func (cctx *CommandContext) bounded() bool {
+	return cctx.variadicMin > 0 || cctx.variadicMax > 0
+}
+
+// boundsString describes the number of variadic arguments allowed, such as
+// "1 to 5".
+func (cctx *CommandContext) boundsString() string {
+	switch {
+	case cctx.variadicMax == 0:
+		return "at least " + strconv.Itoa(cctx.variadicMin)
+	case cctx.variadicMin == 0:
+		return "at most " + strconv.Itoa(cctx.variadicMax)
+	case cctx.variadicMin == cctx.variadicMax:
+		return strconv.Itoa(cctx.variadicMin)
+	default:
+		return strconv.Itoa(cctx.variadicMin) + " to " + strconv.Itoa(cctx.variadicMax)
+	}
+}
+
+// mutableFlags are the flags that can be changed after reflection. The other
+// flags decide how the method is reflected, so changing them would do
+// nothing.
+const mutableFlags = AdminOnly | GuildOnly | DMOnly | Hidden
+
+// SetFlag adds the given flags to the command. Only AdminOnly, GuildOnly,
+// DMOnly and Hidden can be set. Like the A flag, AdminOnly also sets
+// GuildOnly. Commands made Hidden this way are left out of the help, but can
+// still be called.
+func (cctx *CommandContext) SetFlag(flag NameFlag) error {
+	if flag&^mutableFlags != 0 {
+		return errors.New("Only AdminOnly, GuildOnly, DMOnly and Hidden can be set")
+	}
+
+	if flag.Is(AdminOnly) {
+		flag |= GuildOnly
+	}
+
+	cctx.Flag |= flag
+	return nil
+}
+
+// ClearFlag removes the given flags from the command. Only AdminOnly,
+// GuildOnly, DMOnly and Hidden can be cleared.
+func (cctx *CommandContext) ClearFlag(flag NameFlag) error {
+	if flag&^mutableFlags != 0 {
+		return errors.New("Only AdminOnly, GuildOnly, DMOnly and Hidden can be cleared")
+	}
+
+	cctx.Flag &^= flag
+	return nil
+}
+
+// SetDefaults makes the last len(defaults) arguments optional. Each default is
+// parsed like an argument given by the user, and it is used when the user
+// omits the argument. Variadic commands cannot have defaults.
+//
+//    // func (c *Commands) List(m *gateway.MessageCreateEvent, page int)
+//    err := sub.FindCommand("List").SetDefaults("1")
+//
+func (cctx *CommandContext) SetDefaults(defaults ...string) error {
+	if cctx.Variadic {
+		return errors.New("Variadic commands cannot have defaults")
+	}
+
+	if len(defaults) > len(cctx.Arguments) {
+		return errors.Errorf(
+			"Too many defaults: %d for %d arguments",
+			len(defaults), len(cctx.Arguments),
+		)
+	}
+
+	var start = len(cctx.Arguments) - len(defaults)
+
+	for i, def := range defaults {
+		arg := &cctx.Arguments[start+i]
+
+		// Arguments parsed from the rest of the content have no single value.
+		if arg.fn == nil {
+			return errors.Errorf("Argument %d cannot have a default", start+i+1)
+		}
+
+		if _, err := arg.fn(def); err != nil {
+			return errors.Wrapf(err, "Invalid default for argument %d", start+i+1)
+		}
+
+		arg.Optional = true
+		arg.def = def
+	}
+
+	return nil
+}
+
 // NewSubcommand is used to make a new subcommand. You usually wouldn't call
-// this function, but instead use (*Context).RegisterSubcommand().
+// this function, but instead use (*Subcommand).RegisterSubcommand().
 func NewSubcommand(cmd interface{}) (*Subcommand, error) {
 	var sub = Subcommand{
 		command: cmd,
@@ -176,7 +437,12 @@ func NewSubcommand(cmd interface{}) (*Subcommand, error) {
 // NeedsName sets the name for this subcommand. Like InitCommands, this
 // shouldn't be called at all, rather you should use RegisterSubcommand.
 func (sub *Subcommand) NeedsName() {
-	sub.StructName = sub.cmdType.Name()
+	sub.setName(sub.cmdType.Name())
+}
+
+// setName sets the struct name and parses the command name and flags from it.
+func (sub *Subcommand) setName(structName string) {
+	sub.StructName = structName
 
 	flag, name := ParseFlag(sub.StructName)
 
@@ -188,37 +454,262 @@ func (sub *Subcommand) NeedsName() {
 	sub.Flag = flag
 }
 
+// fullCommand returns the name of the subcommand as typed by the user, which
+// includes the names of the subcommands that it's nested in.
+func (sub *Subcommand) fullCommand() string {
+	if sub.parent == nil {
+		return sub.Command
+	}
+	if parent := sub.parent.fullCommand(); parent != "" {
+		return parent + " " + sub.Command
+	}
+	return sub.Command
+}
+
+// Subcommands returns the slice of subcommands nested directly in this one.
+// To add subcommands, use RegisterSubcommand().
+func (sub *Subcommand) Subcommands() []*Subcommand {
+	// Getter is not useless, refer to the struct doc for reason.
+	sub.subMutex.RLock()
+	defer sub.subMutex.RUnlock()
+
+	return sub.subcommands
+}
+
+// allSubcommands returns all subcommands nested in this one at any depth,
+// each followed by its own nested subcommands.
+func (sub *Subcommand) allSubcommands() []*Subcommand {
+	var subs []*Subcommand
+	for _, s := range sub.Subcommands() {
+		subs = append(subs, s)
+		subs = append(subs, s.allSubcommands()...)
+	}
+	return subs
+}
+
+// findSubcommand returns the subcommand with the given name, or nil if there's
+// none.
+func findSubcommand(subs []*Subcommand, name string) *Subcommand {
+	for _, s := range subs {
+		if s.Command == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// MustRegisterSubcommand tries to register a subcommand, and will panic if it
+// fails. This is recommended, as subcommands won't change after initializing
+// once in runtime, thus fairly harmless after development.
+func (sub *Subcommand) MustRegisterSubcommand(cmd interface{}) *Subcommand {
+	s, err := sub.RegisterSubcommand(cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// RegisterSubcommand registers and adds cmd to the list of subcommands. It will
+// also return the resulting Subcommand.
+//
+// Subcommands can be nested by registering them on another subcommand, after
+// that one is registered itself. Each level adds a word to the command:
+//
+//    // ~config server prefix set !
+//    config := ctx.MustRegisterSubcommand(&Config{})
+//    server := config.MustRegisterSubcommand(&Server{})
+//    server.MustRegisterSubcommand(&Prefix{})
+//
+// Nested subcommands inherit the AdminOnly, GuildOnly, DMOnly and Hidden flags
+// of the subcommand they're nested in, which their commands then inherit as
+// usual.
+func (sub *Subcommand) RegisterSubcommand(cmd interface{}) (*Subcommand, error) {
+	return sub.RegisterSubcommandValue("", cmd)
+}
+
+// RegisterSubcommandValue is like RegisterSubcommand, but name is used instead
+// of the struct's type name if it's not empty. The name may contain flags, the
+// same way a struct name would. This allows the same struct pointer to be
+// registered more than once, including as the main commands, as long as the
+// names are unique.
+func (sub *Subcommand) RegisterSubcommandValue(name string, cmd interface{}) (*Subcommand, error) {
+	if sub.ctx == nil {
+		return nil, errors.New("Subcommand must be registered before nesting others in it")
+	}
+
+	s, err := NewSubcommand(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to add subcommand")
+	}
+
+	// Register the subcommand's name.
+	if name != "" {
+		s.setName(name)
+	} else {
+		s.NeedsName()
+	}
+
+	// Nested subcommands inherit their parent's flags, but the main commands'
+	// flags were never inherited.
+	if sub.parent != nil {
+		s.Flag |= sub.Flag & mutableFlags
+	}
+
+	s.parent = sub
+
+	if err := s.InitCommands(sub.ctx); err != nil {
+		return nil, errors.Wrap(err, "Failed to initialize subcommand")
+	}
+
+	sub.subMutex.Lock()
+
+	// Do a collision check
+	if findSubcommand(sub.subcommands, s.Command) != nil {
+		sub.subMutex.Unlock()
+		return nil, errors.New("New subcommand has duplicate name: " + s.Command)
+	}
+
+	sub.subcommands = append(sub.subcommands, s)
+	sub.subMutex.Unlock()
+
+	// The cache is reset after the lock is released, since filling it takes
+	// the lock too.
+	sub.ctx.resetTypeCache()
+	return s, nil
+}
+
+// UnregisterSubcommand removes the subcommand nested directly in this one
+// with the given command or struct name, along with everything nested in it.
+// Its commands and event handlers won't be called afterwards, though the ones
+// already running finish as usual. False is returned if there's no such
+// subcommand. It's safe to call while events are being handled, including
+// from a command.
+func (sub *Subcommand) UnregisterSubcommand(name string) bool {
+	if !sub.removeSubcommand(name) {
+		return false
+	}
+
+	// Stop routing events to the removed subcommand's handlers.
+	if sub.ctx != nil {
+		sub.ctx.resetTypeCache()
+	}
+	return true
+}
+
+func (sub *Subcommand) removeSubcommand(name string) bool {
+	sub.subMutex.Lock()
+	defer sub.subMutex.Unlock()
+
+	for i, s := range sub.subcommands {
+		if s.Command != name && s.StructName != name {
+			continue
+		}
+
+		// Copy, as the old slice may still be read.
+		var subs = make([]*Subcommand, 0, len(sub.subcommands)-1)
+		subs = append(subs, sub.subcommands[:i]...)
+		subs = append(subs, sub.subcommands[i+1:]...)
+
+		sub.subcommands = subs
+		return true
+	}
+
+	return false
+}
+
 // FindCommand finds the command. Nil is returned if nothing is found. It's a
-// better idea to not handle nil, as they would become very subtle bugs.
+// better idea to not handle nil, as they would become very subtle bugs. Use
+// FindCommandErr to handle the not found case explicitly.
 func (sub *Subcommand) FindCommand(methodName string) *CommandContext {
+	c, _ := sub.FindCommandErr(methodName)
+	return c
+}
+
+// FindCommandErr finds the command. An error wrapping ErrCommandNotFound is
+// returned if nothing is found.
+func (sub *Subcommand) FindCommandErr(methodName string) (*CommandContext, error) {
 	for _, c := range sub.Commands {
-		if c.MethodName != methodName {
-			continue
+		if c.MethodName == methodName {
+			return c, nil
 		}
-		return c
 	}
+	return nil, errors.Wrap(ErrCommandNotFound, methodName)
+}
+
+// IsPlumbed returns true if one of the subcommand's methods has the Plumb flag,
+// in which case that method handles all of the subcommand's invocations.
+func (sub *Subcommand) IsPlumbed() bool {
+	return sub.plumb
+}
+
+// PlumbCommand returns the command that the subcommand is plumbed to, or nil if
+// it isn't plumbed. Its MethodName is set, but its Command is empty.
+func (sub *Subcommand) PlumbCommand() *CommandContext {
+	if !sub.plumb {
+		return nil
+	}
+	return sub.Commands[0]
+}
+
+// AddAliases adds aliases to the matched methodName's command. Like the
+// command name, the first letter of each alias is lower-cased unless the
+// command or the subcommand has the Raw flag.
+func (sub *Subcommand) AddAliases(methodName string, aliases ...string) error {
+	c, err := sub.FindCommandErr(methodName)
+	if err != nil {
+		return err
+	}
+
+	// The subcommand's flags may not be inherited yet, such as in Setup.
+	var raw = (c.Flag | sub.Flag).Is(Raw)
+
+	for _, alias := range aliases {
+		if !raw {
+			alias = lowerFirstLetter(alias)
+		}
+		c.Aliases = append(c.Aliases, alias)
+	}
+
 	return nil
 }
 
+// SetCommandFlag adds the given flags to the matched methodName's command.
+// Refer to (*CommandContext).SetFlag.
+func (sub *Subcommand) SetCommandFlag(methodName string, flag NameFlag) error {
+	c, err := sub.FindCommandErr(methodName)
+	if err != nil {
+		return err
+	}
+	return c.SetFlag(flag)
+}
+
+// ClearCommandFlag removes the given flags from the matched methodName's
+// command. Refer to (*CommandContext).ClearFlag.
+func (sub *Subcommand) ClearCommandFlag(methodName string, flag NameFlag) error {
+	c, err := sub.FindCommandErr(methodName)
+	if err != nil {
+		return err
+	}
+	return c.ClearFlag(flag)
+}
+
 // ChangeCommandInfo changes the matched methodName's Command and Description.
 // Empty means unchanged. The returned bool is true when the method is found.
 func (sub *Subcommand) ChangeCommandInfo(methodName, cmd, desc string) bool {
-	for _, c := range sub.Commands {
-		if c.MethodName != methodName {
-			continue
-		}
-
-		if cmd != "" {
-			c.Command = cmd
-		}
-		if desc != "" {
-			c.Description = desc
-		}
+	c := sub.FindCommand(methodName)
+	if c == nil {
+		return false
+	}
 
-		return true
+	if cmd != "" {
+		c.Command = cmd
+	}
+	if desc != "" {
+		c.Description = desc
 	}
 
-	return false
+	return true
 }
 
 func (sub *Subcommand) Help(indent string, hideAdmin bool) string {
@@ -244,48 +735,85 @@ func (sub *Subcommand) Help(indent string, hideAdmin bool) string {
 	header += "\n"
 
 	// The commands part:
-	var commands = ""
+	var commands = sub.helpLines(indent, hideAdmin)
 
-	for i, cmd := range sub.Commands {
-		if cmd.Flag.Is(AdminOnly) && hideAdmin {
-			continue
+	// Nested subcommands are indented under their parent.
+	for _, s := range sub.Subcommands() {
+		if help := s.Help(indent, hideAdmin); help != "" {
+			for _, line := range strings.Split(help, "\n") {
+				commands = append(commands, indent+line)
+			}
 		}
+	}
 
-		switch {
-		case sub.Command != "" && cmd.Command != "":
-			commands += indent + sub.Command + " " + cmd.Command
-		case sub.Command != "":
-			commands += indent + sub.Command
-		default:
-			commands += indent + cmd.Command
-		}
+	if len(commands) == 0 {
+		return ""
+	}
 
-		// Write the usages first.
-		for _, usage := range cmd.Usage() {
-			commands += " " + underline(usage)
-		}
+	return header + strings.Join(commands, "\n")
+}
 
-		// Is the last argument trailing? If so, append ellipsis.
-		if cmd.Variadic {
-			commands += "..."
-		}
+// helpLines generates the help line of each command that isn't hidden.
+func (sub *Subcommand) helpLines(indent string, hideAdmin bool) []string {
+	var lines = make([]string, 0, len(sub.Commands))
 
-		// Write the description if there's any.
-		if cmd.Description != "" {
-			commands += ": " + cmd.Description
+	for _, cmd := range sub.Commands {
+		if cmd.Flag.Is(Hidden) || (cmd.Flag.Is(AdminOnly) && hideAdmin) {
+			continue
 		}
 
-		// Add a new line if this isn't the last command.
-		if i != len(sub.Commands)-1 {
-			commands += "\n"
-		}
+		lines = append(lines, sub.helpCommand(indent, cmd))
 	}
 
-	if commands == "" {
-		return ""
+	return lines
+}
+
+// helpCommand generates the help line for a single command, which contains the
+// command's name, aliases, usage and description.
+func (sub *Subcommand) helpCommand(indent string, cmd *CommandContext) string {
+	var help = indent
+	var name = sub.fullCommand()
+
+	switch {
+	case name != "" && cmd.Command != "":
+		help += name + " " + cmd.Command
+	case name != "":
+		help += name
+	default:
+		help += cmd.Command
+	}
+
+	// Write the aliases, if any.
+	if len(cmd.Aliases) > 0 {
+		help += " (" + strings.Join(cmd.Aliases, ", ") + ")"
+	}
+
+	// Write the usages first.
+	for _, usage := range cmd.Usage() {
+		help += " " + underline(usage)
+	}
+
+	// Is the last argument trailing? If so, append ellipsis, unless the
+	// argument already describes everything it takes. Bounded usages already
+	// have it.
+	if n := len(cmd.Arguments); cmd.Variadic && !cmd.bounded() && (n == 0 || !cmd.Arguments[n-1].full) {
+		help += "..."
+	}
+
+	// Write the description if there's any.
+	if cmd.Description != "" {
+		help += ": " + cmd.Description
+	}
+
+	// Admin commands are always in guilds, so that's not worth mentioning.
+	switch {
+	case cmd.Flag.Is(DMOnly):
+		help += " (DMs only)"
+	case cmd.Flag.Is(GuildOnly) && !cmd.Flag.Is(AdminOnly):
+		help += " (guild only)"
 	}
 
-	return header + commands
+	return help
 }
 
 func (sub *Subcommand) reflectCommands() error {
@@ -317,6 +845,8 @@ func (sub *Subcommand) reflectCommands() error {
 // InitCommands fills a Subcommand with a context. This shouldn't be called at
 // all, rather you should use the RegisterSubcommand method of a Context.
 func (sub *Subcommand) InitCommands(ctx *Context) error {
+	sub.ctx = ctx
+
 	// Start filling up a *Context field
 	if err := sub.fillStruct(ctx); err != nil {
 		return err
@@ -327,6 +857,13 @@ func (sub *Subcommand) InitCommands(ctx *Context) error {
 		v.Setup(sub)
 	}
 
+	// Drop the methods returning data that can't be replied.
+	if !sub.ReplyJSON {
+		sub.Commands = dropUnknownReplies(sub.Commands)
+		sub.Events = dropUnknownReplies(sub.Events)
+		sub.plumb = sub.plumb && len(sub.Commands) > 0
+	}
+
 	// Finalize the subcommand:
 	for _, cmd := range sub.Commands {
 		// Inherit parent's flags
@@ -336,6 +873,27 @@ func (sub *Subcommand) InitCommands(ctx *Context) error {
 	return nil
 }
 
+func dropUnknownReplies(cmds []*CommandContext) []*CommandContext {
+	var kept = cmds[:0]
+	for _, cmd := range cmds {
+		if !cmd.unknownReply {
+			kept = append(kept, cmd)
+		}
+	}
+	return kept
+}
+
+// isReplyType returns true if the data returned before the error is replied
+// without ReplyJSON.
+func isReplyType(t reflect.Type) bool {
+	switch t {
+	case typeString, typeEmbed, typeEmbeds, typeSend, typeFile:
+		return true
+	default:
+		return false
+	}
+}
+
 func (sub *Subcommand) fillStruct(ctx *Context) error {
 	for i := 0; i < sub.cmdValue.NumField(); i++ {
 		field := sub.cmdValue.Field(i)
@@ -365,47 +923,25 @@ func (sub *Subcommand) parseCommands() error {
 			continue
 		}
 
-		methodT := method.Type()
-		numArgs := methodT.NumIn()
-
-		if numArgs == 0 {
-			// Doesn't meet the requirement for an event, continue.
-			continue
-		}
-
-		if methodT == typeSetupFn {
+		if method.Type() == typeSetupFn {
 			// Method is a setup method, continue.
 			continue
 		}
 
-		// Check number of returns:
-		numOut := methodT.NumOut()
+		// The method as it's written, such as testc.Send, for errors.
+		var goName = sub.ptrType.Method(i).Name
+		var fullName = sub.ptrType.Elem().Name() + "." + goName
 
-		// Returns can either be:
-		// Nothing                     - func()
-		// An error                    - func() error
-		// An error and something else - func() (T, error)
-		if numOut > 2 {
-			continue
+		command, err := sub.newCommand(method, goName, fullName)
+		if err != nil {
+			return err
 		}
-
-		// Check the last return's type if the method returns anything.
-		if numOut > 0 {
-			if i := methodT.Out(numOut - 1); i == nil || !i.Implements(typeIError) {
-				// Invalid, skip.
-				continue
-			}
-		}
-
-		var command = CommandContext{
-			method:   sub.ptrType.Method(i),
-			value:    method,
-			event:    methodT.In(0), // parse event
-			Variadic: methodT.IsVariadic(),
+		if command == nil {
+			continue
 		}
 
 		// Parse the method name
-		flag, name := ParseFlag(command.method.Name)
+		flag, name := ParseFlag(goName)
 
 		// Set the method name, command, and flag:
 		command.MethodName = name
@@ -417,72 +953,212 @@ func (sub *Subcommand) parseCommands() error {
 			command.Command = lowerFirstLetter(name)
 		}
 
-		// Middlewares shouldn't even have arguments.
-		if flag.Is(Middleware) {
-			sub.mwMethods = append(sub.mwMethods, &command)
-			continue
+		sub.addCommand(command)
+	}
+
+	return nil
+}
+
+// newCommand reflects the function into a command. The name is the function's
+// name, which may have flags, and fullName is used in errors. A nil command is
+// returned if the function's signature doesn't fit a command.
+func (sub *Subcommand) newCommand(fn reflect.Value, name, fullName string) (*CommandContext, error) {
+	fnT := fn.Type()
+	numArgs := fnT.NumIn()
+
+	if numArgs == 0 {
+		// Doesn't meet the requirement for an event, continue.
+		return nil, nil
+	}
+
+	// Methods may take a context.Context before the event. If that's the
+	// case, then everything else is shifted by one.
+	var argStart int
+	if fnT.In(0) == typeIContext {
+		if numArgs == argStart+1 {
+			return nil, nil
 		}
+		argStart = 1
+	}
 
-		// TODO: allow more flexibility
-		if command.event != typeMessageCreate || flag.Is(Hidden) {
-			sub.Events = append(sub.Events, &command)
-			continue
+	// Check number of returns:
+	numOut := fnT.NumOut()
+
+	// Returns can either be:
+	// Nothing                     - func()
+	// An error                    - func() error
+	// An error and something else - func() (T, error)
+	// Content, embed and an error - func() (string, *discord.Embed, error)
+	if numOut > 3 {
+		return nil, nil
+	}
+
+	if numOut == 3 && (fnT.Out(0) != typeString || fnT.Out(1) != typeEmbed) {
+		return nil, nil
+	}
+
+	// Check the last return's type if the method returns anything.
+	if numOut > 0 {
+		if i := fnT.Out(numOut - 1); i == nil || !i.Implements(typeIError) {
+			// Invalid, skip.
+			return nil, nil
 		}
+	}
 
-		// See if we know the first return type, if error's return is the
-		// second:
-		if numOut > 1 {
-			switch t := methodT.Out(0); t {
-			case typeString, typeEmbed, typeSend:
-				// noop, passes
-			default:
-				continue
-			}
+	// Other data is only replied with ReplyJSON. It's usually set in Setup,
+	// which runs after the methods are parsed, so InitCommands drops these
+	// commands instead.
+	var unknownReply = numOut == 2 && !isReplyType(fnT.Out(0))
+	if unknownReply && sub.ctx != nil && !sub.ReplyJSON {
+		return nil, nil
+	}
+
+	var command = CommandContext{
+		value:    fn,
+		event:    fnT.In(argStart), // parse event
+		Variadic: fnT.IsVariadic(),
+
+		withContext:  argStart > 0,
+		unknownReply: unknownReply,
+	}
+
+	var flag, _ = ParseFlag(name)
+
+	// Middlewares and events other than commands don't have arguments. Neither
+	// do commands that are ignored for a plumb.
+	if flag.Is(Middleware) || command.event != typeMessageCreate || flag.Is(Hidden) ||
+		sub.plumb {
+
+		return &command, nil
+	}
+
+	// The event may be followed by Arguments, which isn't parsed.
+	var firstArg = argStart + 1
+	if numArgs > firstArg && fnT.In(firstArg) == typeArguments {
+		command.withArguments = true
+		firstArg++
+	}
+
+	// If the method only takes an event:
+	if numArgs == firstArg {
+		return &command, nil
+	}
+
+	command.Arguments = make([]Argument, 0, numArgs-firstArg)
+
+	// Fill up arguments. This should work with cusP and manP
+	for i := firstArg; i < numArgs; i++ {
+		t := fnT.In(i)
+		a, err := newArgument(t, command.Variadic)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s argument %d", fullName, i-firstArg+1)
 		}
 
-		// If a plumb method has been found:
-		if sub.plumb {
-			continue
+		if a.rtype == typeContent && i != firstArg {
+			return nil, errors.New("RawContent must be the only argument of " + fullName)
 		}
 
-		// If the method only takes an event:
-		if numArgs == 1 {
-			sub.Commands = append(sub.Commands, &command)
-			continue
+		command.Arguments = append(command.Arguments, *a)
+
+		// We're done if the type accepts multiple arguments.
+		if a.custom != nil || a.manual != nil || a.tagged != nil || a.flags != nil {
+			command.Variadic = true // treat as variadic
+			break
 		}
+	}
 
-		command.Arguments = make([]Argument, 0, numArgs)
+	return &command, nil
+}
 
-		// Fill up arguments. This should work with cusP and manP
-		for i := 1; i < numArgs; i++ {
-			t := methodT.In(i)
-			a, err := newArgument(t, command.Variadic)
-			if err != nil {
-				return errors.Wrap(err, "Error parsing argument "+t.String())
-			}
+// addCommand adds the command to the list its flag and event belong to.
+func (sub *Subcommand) addCommand(command *CommandContext) {
+	switch {
+	// Middlewares shouldn't even have arguments.
+	case command.Flag.Is(Middleware):
+		sub.mwMethods = append(sub.mwMethods, command)
 
-			command.Arguments = append(command.Arguments, *a)
+	// TODO: allow more flexibility
+	case command.event != typeMessageCreate || command.Flag.Is(Hidden):
+		sub.Events = append(sub.Events, command)
 
-			// We're done if the type accepts multiple arguments.
-			if a.custom != nil || a.manual != nil {
-				command.Variadic = true // treat as variadic
-				break
-			}
-		}
+	// If a plumb method has been found:
+	case sub.plumb:
 
-		// If the current event is a plumb event:
-		if flag.Is(Plumb) {
-			command.Command = "" // plumbers don't have names
-			sub.Commands = []*CommandContext{&command}
-			sub.plumb = true
-			continue
+	// If the current event is a plumb event:
+	case command.Flag.Is(Plumb):
+		command.Command = "" // plumbers don't have names
+		sub.Commands = []*CommandContext{command}
+		sub.plumb = true
+
+	default:
+		sub.Commands = append(sub.Commands, command)
+	}
+}
+
+// AddCommand adds a command that isn't a method, such as one made at runtime
+// for each loaded plugin. The function is checked like methods are, and must
+// take a *gateway.MessageCreateEvent, after an optional context.Context, then
+// the arguments. The name is used as it's given, and may have flags. This is
+// usually called in Setup:
+//
+//    func (b *Bot) Setup(sub *bot.Subcommand) {
+//        for _, p := range plugins {
+//            p := p
+//            sub.AddCommand(p.Name, func(*gateway.MessageCreateEvent, ...string) (string, error) {
+//                return p.Run()
+//            }, p.Description)
+//        }
+//    }
+//
+// Commands added after the Subcommand is added to a Context don't inherit its
+// flags. The command can't be a Plumb.
+//
+// AddCommand isn't thread-safe, so it must not be called while events are
+// being handled. Call it in Setup or before the gateway is opened instead.
+func (sub *Subcommand) AddCommand(name string, fn interface{}, desc string) (*CommandContext, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return nil, errors.New("command " + name + " is not a function")
+	}
+
+	command, err := sub.newCommand(v, name, name)
+	if err != nil {
+		return nil, err
+	}
+	if command == nil {
+		return nil, errors.New("invalid signature for command " + name + ": " + v.Type().String())
+	}
+
+	flag, cmdName := ParseFlag(name)
+	if cmdName == "" {
+		return nil, errors.New("command name is empty")
+	}
+	if flag.Is(Plumb) {
+		return nil, errors.New("command " + cmdName + " can't be a plumb")
+	}
+
+	if command.event == typeMessageCreate && !flag.Is(Hidden) && !flag.Is(Middleware) {
+		if sub.plumb {
+			return nil, errors.New("can't add command " + cmdName + " to a plumbed subcommand")
+		}
+		if findCommand(sub.Commands, cmdName, false) != nil {
+			return nil, errors.New("command " + cmdName + " already exists")
 		}
+	}
+
+	command.MethodName = cmdName
+	command.Command = cmdName
+	command.Flag = flag
+	command.Description = desc
 
-		// Append
-		sub.Commands = append(sub.Commands, &command)
+	sub.addCommand(command)
+
+	// Event handlers are cached, so the cache has to be filled again.
+	if sub.ctx != nil {
+		sub.ctx.resetTypeCache()
 	}
 
-	return nil
+	return command, nil
 }
 
 func lowerFirstLetter(name string) string {
@@ -1,7 +1,13 @@
 package bot
 
 import (
+	"errors"
+	"strings"
 	"testing"
+
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/diamondburned/arikawa/state"
 )
 
 func TestNewSubcommand(t *testing.T) {
@@ -29,7 +35,7 @@ func TestSubcommand(t *testing.T) {
 		}
 
 		// !!! CHANGE ME
-		if len(sub.Commands) != 8 {
+		if len(sub.Commands) != 12 {
 			t.Fatal("invalid ctx.commands len", len(sub.Commands))
 		}
 
@@ -41,6 +47,11 @@ func TestSubcommand(t *testing.T) {
 
 		for _, this := range sub.Commands {
 			switch this.Command {
+			case "withContext":
+				if !this.withContext || len(this.Arguments) != 1 {
+					t.Fatal("invalid withContext command")
+				}
+
 			case "send":
 				foundSend = true
 				if len(this.Arguments) != 1 {
@@ -81,6 +92,43 @@ func TestSubcommand(t *testing.T) {
 		}
 	})
 
+	t.Run("find commands", func(t *testing.T) {
+		if _, err := sub.FindCommandErr("NoArgs"); err != nil {
+			t.Fatal("Failed to find NoArgs:", err)
+		}
+
+		_, err := sub.FindCommandErr("Missing")
+		if !errors.Is(err, ErrCommandNotFound) {
+			t.Fatal("Unexpected error:", err)
+		}
+
+		if cmd := sub.FindCommand("Missing"); cmd != nil {
+			t.Fatal("Unexpected command found:", cmd.MethodName)
+		}
+	})
+
+	t.Run("set flags", func(t *testing.T) {
+		if err := sub.SetCommandFlag("Noop", AdminOnly); err != nil {
+			t.Fatal("Failed to set flag:", err)
+		}
+
+		cmd := sub.FindCommand("Noop")
+		if !cmd.Flag.Is(AdminOnly) || !cmd.Flag.Is(GuildOnly) {
+			t.Fatal("Unexpected flags:", cmd.Flag)
+		}
+
+		if err := sub.ClearCommandFlag("Noop", AdminOnly|GuildOnly); err != nil {
+			t.Fatal("Failed to clear flag:", err)
+		}
+		if cmd.Flag != 0 {
+			t.Fatal("Unexpected flags after clearing:", cmd.Flag)
+		}
+
+		if err := cmd.SetFlag(Plumb); err == nil {
+			t.Fatal("Expected error setting Plumb")
+		}
+	})
+
 	t.Run("help commands", func(t *testing.T) {
 		if h := sub.Help("", false); h == "" {
 			t.Fatal("Empty subcommand help?")
@@ -93,3 +141,155 @@ func BenchmarkSubcommandConstructor(b *testing.B) {
 		NewSubcommand(&testc{})
 	}
 }
+
+type testReturns struct {
+	Ctx *Context
+}
+
+func (t *testReturns) Number(*gateway.MessageCreateEvent) (int, error) {
+	return 69420, nil
+}
+
+type testReturnsJSON struct {
+	Ctx *Context
+}
+
+func (t *testReturnsJSON) Number(*gateway.MessageCreateEvent) (int, error) {
+	return 69420, nil
+}
+
+func (t *testReturnsJSON) Setup(sub *Subcommand) {
+	sub.ReplyJSON = true
+}
+
+func TestSubcommandAnyReturn(t *testing.T) {
+	var s = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(s, &testReturns{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	if cmd := c.FindCommand("", "Number"); cmd != nil {
+		t.Fatal("Unexpected command returning (int, error) without ReplyJSON")
+	}
+
+	c, err = New(s, &testReturnsJSON{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+	c.HasPrefix = NewPrefix("!")
+
+	if cmd := c.FindCommand("", "Number"); cmd == nil {
+		t.Fatal("Failed to find command returning (int, error)")
+	}
+
+	replies, err := c.Simulate("!number")
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if len(replies) != 1 || replies[0].Content != "```json\n69420\n```" {
+		t.Fatal("Unexpected replies:", replies)
+	}
+}
+
+type testInvalidArgument struct {
+	Ctx *Context
+}
+
+func (t *testInvalidArgument) Send(_ *gateway.MessageCreateEvent, n int, ch chan int) error {
+	return nil
+}
+
+func TestSubcommandInvalidArgument(t *testing.T) {
+	_, err := NewSubcommand(&testInvalidArgument{})
+	if err == nil {
+		t.Fatal("Expected error for invalid argument")
+	}
+
+	const expects = "testInvalidArgument.Send argument 2: invalid type: chan int"
+	if !strings.HasSuffix(err.Error(), expects) {
+		t.Fatal("Unexpected error:", err)
+	}
+}
+
+type testRuntime struct {
+	Ctx *Context
+}
+
+func (t *testRuntime) Setup(sub *Subcommand) {
+	_, err := sub.AddCommand("echo", func(_ *gateway.MessageCreateEvent, words ...string) (string, error) {
+		return "", errors.New(strings.Join(words, " "))
+	}, "Echoes the words")
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestSubcommandAddCommand(t *testing.T) {
+	var state = &state.State{
+		Store: state.NewDefaultStore(nil),
+	}
+
+	c, err := New(state, &testRuntime{})
+	if err != nil {
+		t.Fatal("Failed to create new context:", err)
+	}
+
+	cmd := c.FindCommand("", "echo")
+	if cmd == nil || cmd.Description != "Echoes the words" || !cmd.Variadic {
+		t.Fatalf("Unexpected command: %+v", cmd)
+	}
+
+	err = c.callCmd(&gateway.MessageCreateEvent{
+		Message: discord.Message{Content: "~echo hello world"},
+	})
+	if err == nil || err.Error() != "hello world" {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	var tests = []struct {
+		name   string
+		fn     interface{}
+		expect string
+	}{
+		{"echo", func(*gateway.MessageCreateEvent) error { return nil }, "command echo already exists"},
+		{"bad", "not a function", "command bad is not a function"},
+		{"bad", func() {}, "invalid signature for command bad: func()"},
+		{"bad", func(*gateway.MessageCreateEvent, chan int) error { return nil }, "bad argument 1: invalid type: chan int"},
+		{"PーAll", func(*gateway.MessageCreateEvent) error { return nil }, "command All can't be a plumb"},
+	}
+
+	for _, test := range tests {
+		_, err := c.AddCommand(test.name, test.fn, "")
+		if err == nil || err.Error() != test.expect {
+			t.Errorf("AddCommand(%q): unexpected error: %v", test.name, err)
+		}
+	}
+
+	// Event handlers added after events are handled are called too.
+	if err := c.callCmd(&gateway.TypingStartEvent{}); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	var typing = make(chan struct{}, 1)
+
+	_, err = c.AddCommand("typing", func(*gateway.TypingStartEvent) {
+		typing <- struct{}{}
+	}, "")
+	if err != nil {
+		t.Fatal("Failed to add event handler:", err)
+	}
+
+	if err := c.callCmd(&gateway.TypingStartEvent{}); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	select {
+	case <-typing:
+	default:
+		t.Fatal("Event handler wasn't called")
+	}
+}
@@ -0,0 +1,88 @@
+package bot
+
+// suggestCommand returns the candidate closest to name, or an empty string if
+// none of them are within maxDist edits.
+func suggestCommand(name string, maxDist int, candidates []string) string {
+	var best string
+	var bestDist = maxDist + 1
+
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+
+		if d := editDistance(name, candidate); d < bestDist {
+			best = candidate
+			bestDist = d
+		}
+	}
+
+	return best
+}
+
+// commandNames returns the names and aliases of the given commands. Hidden and
+// AdminOnly commands are left out, so they can't be found through typos.
+func commandNames(cmds []*CommandContext) []string {
+	var names = make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		if cmd.Flag.Is(Hidden | AdminOnly) {
+			continue
+		}
+		names = append(names, cmd.Command)
+		names = append(names, cmd.Aliases...)
+	}
+	return names
+}
+
+// subcommandNames returns the names of the subcommand's commands and of the
+// subcommands nested in it, leaving out the Hidden and AdminOnly ones.
+func subcommandNames(sub *Subcommand) []string {
+	var names = commandNames(sub.Commands)
+	for _, s := range sub.Subcommands() {
+		if s.Flag.Is(Hidden | AdminOnly) {
+			continue
+		}
+		names = append(names, s.Command)
+	}
+	return names
+}
+
+// editDistance calculates the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	var ra, rb = []rune(a), []rune(b)
+
+	// Only keep the previous and current rows.
+	var prev = make([]int, len(rb)+1)
+	var curr = make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			var cost = 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,64 @@
+package bot
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	var tests = []struct {
+		a, b string
+		dist int
+	}{
+		{"", "", 0},
+		{"send", "send", 0},
+		{"sned", "send", 2},
+		{"sen", "send", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+
+	for _, test := range tests {
+		if d := editDistance(test.a, test.b); d != test.dist {
+			t.Errorf("Distance between %q and %q: expected %d, got %d", test.a, test.b, test.dist, d)
+		}
+	}
+}
+
+func TestSuggestCommand(t *testing.T) {
+	var names = []string{"send", "noArgs", "getCounter", "gc"}
+
+	if s := suggestCommand("sen", 2, names); s != "send" {
+		t.Fatal("Unexpected suggestion:", s)
+	}
+
+	if s := suggestCommand("noargs", 2, names); s != "noArgs" {
+		t.Fatal("Unexpected suggestion:", s)
+	}
+
+	if s := suggestCommand("completely different", 2, names); s != "" {
+		t.Fatal("Unexpected suggestion:", s)
+	}
+}
+
+func TestCommandNamesHidden(t *testing.T) {
+	var cmds = []*CommandContext{
+		{Command: "send", Aliases: []string{"s"}},
+		{Command: "ban", Flag: AdminOnly},
+		{Command: "debug", Flag: Hidden},
+	}
+
+	names := commandNames(cmds)
+	if len(names) != 2 || names[0] != "send" || names[1] != "s" {
+		t.Fatal("Unexpected names:", names)
+	}
+
+	var sub = &Subcommand{
+		subcommands: []*Subcommand{
+			{Command: "config", Flag: AdminOnly},
+			{Command: "info"},
+		},
+	}
+
+	names = subcommandNames(sub)
+	if len(names) != 1 || names[0] != "info" {
+		t.Fatal("Unexpected subcommand names:", names)
+	}
+}
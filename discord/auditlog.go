@@ -0,0 +1,326 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// https://discord.com/developers/docs/resources/audit-log#audit-log-object
+type AuditLog struct {
+	Webhooks     []Webhook       `json:"webhooks"`
+	Users        []User          `json:"users"`
+	Entries      []AuditLogEntry `json:"audit_log_entries"`
+	Integrations []Integration   `json:"integrations"`
+}
+
+// User returns the user with the given ID from the audit log's users, or nil
+// if it's not there.
+func (l *AuditLog) User(id Snowflake) *User {
+	for i := range l.Users {
+		if l.Users[i].ID == id {
+			return &l.Users[i]
+		}
+	}
+	return nil
+}
+
+// Webhook returns the webhook with the given ID from the audit log's webhooks,
+// or nil if it's not there.
+func (l *AuditLog) Webhook(id Snowflake) *Webhook {
+	for i := range l.Webhooks {
+		if l.Webhooks[i].ID == id {
+			return &l.Webhooks[i]
+		}
+	}
+	return nil
+}
+
+type AuditLogEntry struct {
+	ID       Snowflake `json:"id"`
+	TargetID Snowflake `json:"target_id"` // zero if there's no target
+
+	Changes []AuditLogChange `json:"changes,omitempty"`
+
+	UserID     Snowflake      `json:"user_id"`
+	ActionType AuditLogEvent  `json:"action_type"`
+	Options    AuditEntryInfo `json:"options"`
+
+	Reason string `json:"reason,omitempty"`
+}
+
+type AuditLogEvent uint8
+
+const (
+	GuildUpdate AuditLogEvent = 1
+)
+
+const (
+	ChannelCreate AuditLogEvent = iota + 10
+	ChannelUpdate
+	ChannelDelete
+	ChannelOverwriteCreate
+	ChannelOverwriteUpdate
+	ChannelOverwriteDelete
+)
+
+const (
+	MemberKick AuditLogEvent = iota + 20
+	MemberPrune
+	MemberBanAdd
+	MemberBanRemove
+	MemberUpdate
+	MemberRoleUpdate
+	MemberMove
+	MemberDisconnect
+	BotAdd
+)
+
+const (
+	RoleCreate AuditLogEvent = iota + 30
+	RoleUpdate
+	RoleDelete
+)
+
+const (
+	InviteCreate AuditLogEvent = iota + 40
+	InviteUpdate
+	InviteDelete
+)
+
+const (
+	WebhookCreate AuditLogEvent = iota + 50
+	WebhookUpdate
+	WebhookDelete
+)
+
+const (
+	EmojiCreate AuditLogEvent = iota + 60
+	EmojiUpdate
+	EmojiDelete
+)
+
+const (
+	MessageDelete AuditLogEvent = iota + 72
+	MessageBulkDelete
+	MessagePin
+	MessageUnpin
+)
+
+const (
+	IntegrationCreate AuditLogEvent = iota + 80
+	IntegrationUpdate
+	IntegrationDelete
+)
+
+// AuditEntryInfo holds the extra information of some events. Only the fields
+// relevant to the entry's ActionType are filled.
+type AuditEntryInfo struct {
+	// MemberPrune
+	DeleteMemberDays string `json:"delete_member_days,omitempty"`
+	MembersRemoved   string `json:"members_removed,omitempty"`
+
+	// MemberMove, MessageDelete, MessagePin and MessageUnpin
+	ChannelID Snowflake `json:"channel_id,omitempty"`
+	// MessagePin and MessageUnpin
+	MessageID Snowflake `json:"message_id,omitempty"`
+	// MemberMove, MemberDisconnect, MessageDelete and MessageBulkDelete
+	Count string `json:"count,omitempty"`
+
+	// ChannelOverwrite events
+	ID       Snowflake     `json:"id,omitempty"`
+	Type     OverwriteType `json:"type,omitempty"`
+	RoleName string        `json:"role_name,omitempty"` // role overwrites only
+}
+
+// AuditLogChange is a single change of an entry. The values are kept as raw
+// JSON, as their type depends on the Key. Use Values to decode them into the
+// type the key has, or UnmarshalValues to decode them into known types:
+//
+//    for _, change := range entry.Changes {
+//        if change.Key == discord.AuditUserNick {
+//            var old, new string
+//            if err := change.UnmarshalValues(&old, &new); err == nil {
+//                log.Println("Nickname changed from", old, "to", new)
+//            }
+//        }
+//    }
+//
+type AuditLogChange struct {
+	Key      AuditLogChangeKey `json:"key"`
+	NewValue json.RawMessage   `json:"new_value,omitempty"`
+	OldValue json.RawMessage   `json:"old_value,omitempty"`
+}
+
+// UnmarshalValues decodes the old and new values into the given pointers.
+// Values that aren't in the change are left untouched, and a nil pointer
+// skips that value.
+func (c AuditLogChange) UnmarshalValues(old, new interface{}) error {
+	if old != nil && len(c.OldValue) > 0 {
+		if err := json.Unmarshal(c.OldValue, old); err != nil {
+			return fmt.Errorf("failed to unmarshal old %s: %w", c.Key, err)
+		}
+	}
+	if new != nil && len(c.NewValue) > 0 {
+		if err := json.Unmarshal(c.NewValue, new); err != nil {
+			return fmt.Errorf("failed to unmarshal new %s: %w", c.Key, err)
+		}
+	}
+	return nil
+}
+
+// Values decodes the old and new values into the type of the change's key, as
+// given by AuditLogChangeKey's Type. The values are returned as such, e.g.
+// Snowflake for AuditGuildOwnerID or []Role for AuditGuildRoleAdd, and are nil
+// if they're not in the change. Values of keys without a type are returned as
+// json.RawMessage.
+func (c AuditLogChange) Values() (old, new interface{}, err error) {
+	var t = c.Key.Type()
+
+	decode := func(raw json.RawMessage) (interface{}, error) {
+		if len(raw) == 0 {
+			return nil, nil
+		}
+		if t == nil {
+			return raw, nil
+		}
+
+		v := reflect.New(t)
+		if err := json.Unmarshal(raw, v.Interface()); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", c.Key, err)
+		}
+		return v.Elem().Interface(), nil
+	}
+
+	if old, err = decode(c.OldValue); err != nil {
+		return nil, nil, err
+	}
+	if new, err = decode(c.NewValue); err != nil {
+		return nil, nil, err
+	}
+	return old, new, nil
+}
+
+// AuditLogChangeKey is the key of a change, which also determines the type of
+// its values.
+//
+// https://discord.com/developers/docs/resources/audit-log#audit-log-change-object-audit-log-change-key
+type AuditLogChangeKey string
+
+const (
+	// Guild
+	AuditGuildName            AuditLogChangeKey = "name"
+	AuditGuildIconHash        AuditLogChangeKey = "icon_hash"
+	AuditGuildSplashHash      AuditLogChangeKey = "splash_hash"
+	AuditGuildOwnerID         AuditLogChangeKey = "owner_id"
+	AuditGuildRegion          AuditLogChangeKey = "region"
+	AuditGuildAFKChannelID    AuditLogChangeKey = "afk_channel_id"
+	AuditGuildAFKTimeout      AuditLogChangeKey = "afk_timeout"
+	AuditGuildMFA             AuditLogChangeKey = "mfa_level"
+	AuditGuildVerification    AuditLogChangeKey = "verification_level"
+	AuditGuildExplicitFilter  AuditLogChangeKey = "explicit_content_filter"
+	AuditGuildNotification    AuditLogChangeKey = "default_message_notifications"
+	AuditGuildVanityURLCode   AuditLogChangeKey = "vanity_url_code"
+	AuditGuildRoleAdd         AuditLogChangeKey = "$add"
+	AuditGuildRoleRemove      AuditLogChangeKey = "$remove"
+	AuditGuildPruneDeleteDays AuditLogChangeKey = "prune_delete_days"
+	AuditGuildWidgetEnabled   AuditLogChangeKey = "widget_enabled"
+	AuditGuildWidgetChannelID AuditLogChangeKey = "widget_channel_id"
+	AuditGuildSystemChannelID AuditLogChangeKey = "system_channel_id"
+
+	// Channel
+	AuditChannelPosition             AuditLogChangeKey = "position"
+	AuditChannelTopic                AuditLogChangeKey = "topic"
+	AuditChannelBitrate              AuditLogChangeKey = "bitrate"
+	AuditChannelPermissionOverwrites AuditLogChangeKey = "permission_overwrites"
+	AuditChannelNSFW                 AuditLogChangeKey = "nsfw"
+	AuditChannelApplicationID        AuditLogChangeKey = "application_id"
+	AuditChannelRateLimitPerUser     AuditLogChangeKey = "rate_limit_per_user"
+
+	// Role
+	AuditRolePermissions AuditLogChangeKey = "permissions"
+	AuditRoleColor       AuditLogChangeKey = "color"
+	AuditRoleHoist       AuditLogChangeKey = "hoist"
+	AuditRoleMentionable AuditLogChangeKey = "mentionable"
+	AuditRoleAllow       AuditLogChangeKey = "allow"
+	AuditRoleDeny        AuditLogChangeKey = "deny"
+
+	// Invite
+	AuditInviteCode      AuditLogChangeKey = "code"
+	AuditInviteChannelID AuditLogChangeKey = "channel_id"
+	AuditInviteInviterID AuditLogChangeKey = "inviter_id"
+	AuditInviteMaxUses   AuditLogChangeKey = "max_uses"
+	AuditInviteUses      AuditLogChangeKey = "uses"
+	AuditInviteMaxAge    AuditLogChangeKey = "max_age"
+	AuditInviteTemporary AuditLogChangeKey = "temporary"
+
+	// Member
+	AuditUserDeaf       AuditLogChangeKey = "deaf"
+	AuditUserMute       AuditLogChangeKey = "mute"
+	AuditUserNick       AuditLogChangeKey = "nick"
+	AuditUserAvatarHash AuditLogChangeKey = "avatar_hash"
+
+	// Any
+	AuditAnyID   AuditLogChangeKey = "id"
+	AuditAnyType AuditLogChangeKey = "type" // ChannelType or string
+
+	// Integration
+	AuditIntegrationEnableEmoticons   AuditLogChangeKey = "enable_emoticons"
+	AuditIntegrationExpireBehavior    AuditLogChangeKey = "expire_behavior"
+	AuditIntegrationExpireGracePeriod AuditLogChangeKey = "expire_grace_period"
+)
+
+var auditLogChangeTypes = map[AuditLogChangeKey]reflect.Type{
+	AuditGuildName:                    reflect.TypeOf(""),
+	AuditGuildIconHash:                reflect.TypeOf(Hash("")),
+	AuditGuildSplashHash:              reflect.TypeOf(Hash("")),
+	AuditGuildOwnerID:                 reflect.TypeOf(Snowflake(0)),
+	AuditGuildRegion:                  reflect.TypeOf(""),
+	AuditGuildAFKChannelID:            reflect.TypeOf(Snowflake(0)),
+	AuditGuildAFKTimeout:              reflect.TypeOf(Seconds(0)),
+	AuditGuildMFA:                     reflect.TypeOf(MFALevel(0)),
+	AuditGuildVerification:            reflect.TypeOf(Verification(0)),
+	AuditGuildExplicitFilter:          reflect.TypeOf(ExplicitFilter(0)),
+	AuditGuildNotification:            reflect.TypeOf(Notification(0)),
+	AuditGuildVanityURLCode:           reflect.TypeOf(""),
+	AuditGuildRoleAdd:                 reflect.TypeOf([]Role(nil)),
+	AuditGuildRoleRemove:              reflect.TypeOf([]Role(nil)),
+	AuditGuildPruneDeleteDays:         reflect.TypeOf(0),
+	AuditGuildWidgetEnabled:           reflect.TypeOf(false),
+	AuditGuildWidgetChannelID:         reflect.TypeOf(Snowflake(0)),
+	AuditGuildSystemChannelID:         reflect.TypeOf(Snowflake(0)),
+	AuditChannelPosition:              reflect.TypeOf(0),
+	AuditChannelTopic:                 reflect.TypeOf(""),
+	AuditChannelBitrate:               reflect.TypeOf(uint(0)),
+	AuditChannelPermissionOverwrites:  reflect.TypeOf([]Overwrite(nil)),
+	AuditChannelNSFW:                  reflect.TypeOf(false),
+	AuditChannelApplicationID:         reflect.TypeOf(Snowflake(0)),
+	AuditChannelRateLimitPerUser:      reflect.TypeOf(Seconds(0)),
+	AuditRolePermissions:              reflect.TypeOf(Permissions(0)),
+	AuditRoleColor:                    reflect.TypeOf(Color(0)),
+	AuditRoleHoist:                    reflect.TypeOf(false),
+	AuditRoleMentionable:              reflect.TypeOf(false),
+	AuditRoleAllow:                    reflect.TypeOf(Permissions(0)),
+	AuditRoleDeny:                     reflect.TypeOf(Permissions(0)),
+	AuditInviteCode:                   reflect.TypeOf(""),
+	AuditInviteChannelID:              reflect.TypeOf(Snowflake(0)),
+	AuditInviteInviterID:              reflect.TypeOf(Snowflake(0)),
+	AuditInviteMaxUses:                reflect.TypeOf(0),
+	AuditInviteUses:                   reflect.TypeOf(0),
+	AuditInviteMaxAge:                 reflect.TypeOf(Seconds(0)),
+	AuditInviteTemporary:              reflect.TypeOf(false),
+	AuditUserDeaf:                     reflect.TypeOf(false),
+	AuditUserMute:                     reflect.TypeOf(false),
+	AuditUserNick:                     reflect.TypeOf(""),
+	AuditUserAvatarHash:               reflect.TypeOf(Hash("")),
+	AuditAnyID:                        reflect.TypeOf(Snowflake(0)),
+	AuditIntegrationEnableEmoticons:   reflect.TypeOf(false),
+	AuditIntegrationExpireBehavior:    reflect.TypeOf(0),
+	AuditIntegrationExpireGracePeriod: reflect.TypeOf(0),
+}
+
+// Type returns the type of the key's values, or nil if the key is unknown or
+// its type varies, as with AuditAnyType.
+func (k AuditLogChangeKey) Type() reflect.Type {
+	return auditLogChangeTypes[k]
+}
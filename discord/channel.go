@@ -1,5 +1,7 @@
 package discord
 
+import "github.com/diamondburned/arikawa/utils/json"
+
 type Channel struct {
 	ID   Snowflake   `json:"id,string"`
 	Type ChannelType `json:"type"`
@@ -39,6 +41,10 @@ type Channel struct {
 	// Voice, so GuildVoice only
 	VoiceBitrate   uint `json:"bitrate,omitempty"`
 	VoiceUserLimit uint `json:"user_limit,omitempty"`
+
+	// Extras holds the fields that the library doesn't know of yet. It's only
+	// filled if json.CaptureExtras is true.
+	Extras json.Extras `json:"-"`
 }
 
 func (ch Channel) Mention() string {
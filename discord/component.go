@@ -0,0 +1,121 @@
+package discord
+
+// https://discord.com/developers/docs/interactions/message-components
+
+type ComponentType uint8
+
+const (
+	ActionRowComponent ComponentType = iota + 1
+	ButtonComponent
+	SelectMenuComponent
+)
+
+type ButtonStyle uint8
+
+const (
+	PrimaryButton ButtonStyle = iota + 1
+	SecondaryButton
+	SuccessButton
+	DangerButton
+	// LinkButton opens its URL instead of sending an interaction, so it has no
+	// CustomID.
+	LinkButton
+)
+
+// Component is a part of a message that users can interact with. A message has
+// up to 5 action rows, each of which holds either up to 5 buttons or a single
+// select menu. Only the fields of the component's Type are used:
+//
+//    data := api.SendMessageData{
+//        Content: "Are you sure?",
+//        Components: []discord.Component{
+//            discord.NewActionRow(
+//                discord.NewButton(discord.SuccessButton, "Yes", "confirm_yes"),
+//                discord.NewButton(discord.DangerButton, "No", "confirm_no"),
+//            ),
+//        },
+//    }
+//
+// Clicking a button or picking from a select menu sends an interaction with the
+// component's CustomID. Refer to Interaction.
+type Component struct {
+	Type ComponentType `json:"type"`
+
+	// CustomID identifies the button or select menu in interactions. It's at
+	// most 100 characters.
+	CustomID string `json:"custom_id,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+
+	// Components are the buttons or select menu of an action row.
+	Components []Component `json:"components,omitempty"`
+
+	// Button fields. A button needs either a Label or an Emoji.
+	Style ButtonStyle     `json:"style,omitempty"`
+	Label string          `json:"label,omitempty"`
+	Emoji *ComponentEmoji `json:"emoji,omitempty"`
+	URL   URL             `json:"url,omitempty"` // LinkButton only
+
+	// Select menu fields. MinValues of 0 means the default of 1, and MaxValues
+	// is at most 25.
+	Placeholder string         `json:"placeholder,omitempty"`
+	MinValues   int            `json:"min_values,omitempty"`
+	MaxValues   int            `json:"max_values,omitempty"`
+	Options     []SelectOption `json:"options,omitempty"`
+}
+
+// ComponentEmoji is the partial emoji shown in buttons and select options. ID
+// is 0 for Unicode emojis.
+type ComponentEmoji struct {
+	ID       Snowflake `json:"id,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Animated bool      `json:"animated,omitempty"`
+}
+
+// SelectOption is an option of a select menu. Value is what's sent in the
+// interaction when the option is picked.
+type SelectOption struct {
+	Label       string          `json:"label"`
+	Value       string          `json:"value"`
+	Description string          `json:"description,omitempty"`
+	Emoji       *ComponentEmoji `json:"emoji,omitempty"`
+	Default     bool            `json:"default,omitempty"`
+}
+
+// NewActionRow creates an action row with the given buttons or select menu.
+func NewActionRow(components ...Component) Component {
+	return Component{
+		Type:       ActionRowComponent,
+		Components: components,
+	}
+}
+
+// NewButton creates a button that sends an interaction with the custom ID when
+// clicked. Use NewLinkButton for LinkButton.
+func NewButton(style ButtonStyle, label, customID string) Component {
+	return Component{
+		Type:     ButtonComponent,
+		Style:    style,
+		Label:    label,
+		CustomID: customID,
+	}
+}
+
+// NewLinkButton creates a button that opens the URL when clicked.
+func NewLinkButton(label string, url URL) Component {
+	return Component{
+		Type:  ButtonComponent,
+		Style: LinkButton,
+		Label: label,
+		URL:   url,
+	}
+}
+
+// NewSelectMenu creates a select menu that sends an interaction with the custom
+// ID and the picked values.
+func NewSelectMenu(customID string, options ...SelectOption) Component {
+	return Component{
+		Type:     SelectMenuComponent,
+		CustomID: customID,
+		Options:  options,
+	}
+}
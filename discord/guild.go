@@ -1,5 +1,7 @@
 package discord
 
+import "github.com/diamondburned/arikawa/utils/json"
+
 type Guild struct {
 	ID     Snowflake `json:"id,string"`
 	Name   string    `json:"name"`
@@ -49,6 +51,10 @@ type Guild struct {
 
 	// Defaults to en-US, only set if guild has DISCOVERABLE
 	PreferredLocale string `json:"preferred_locale"`
+
+	// Extras holds the fields that the library doesn't know of yet. It's only
+	// filled if json.CaptureExtras is true.
+	Extras json.Extras `json:"-"`
 }
 
 // IconURL returns the URL to the guild icon. An empty string is removed if
@@ -141,6 +147,10 @@ type Member struct {
 
 	Deaf bool `json:"deaf"`
 	Mute bool `json:"mute"`
+
+	// Extras holds the fields that the library doesn't know of yet. It's only
+	// filled if json.CaptureExtras is true.
+	Extras json.Extras `json:"-"`
 }
 
 func (m Member) Mention() string {
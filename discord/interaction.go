@@ -0,0 +1,63 @@
+package discord
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding
+
+type InteractionType uint8
+
+const (
+	PingInteraction InteractionType = iota + 1
+	CommandInteraction
+	ComponentInteraction
+)
+
+// Interaction is sent when a user uses an application command or a message
+// component. It has to be responded to within 3 seconds with the Token, after
+// which the Token stays valid for 15 minutes to edit the response and send
+// followup messages.
+type Interaction struct {
+	ID            Snowflake       `json:"id"`
+	ApplicationID Snowflake       `json:"application_id"`
+	Type          InteractionType `json:"type"`
+	Data          InteractionData `json:"data,omitempty"`
+
+	GuildID   Snowflake `json:"guild_id,omitempty"`
+	ChannelID Snowflake `json:"channel_id,omitempty"`
+
+	// Member is sent in guilds, and User in direct messages. Use Sender to get
+	// either.
+	Member *Member `json:"member,omitempty"`
+	User   *User   `json:"user,omitempty"`
+
+	Token   string `json:"token"`
+	Version int    `json:"version"`
+
+	// Message is the message that the component is attached to, if the
+	// interaction is from a component.
+	Message *Message `json:"message,omitempty"`
+}
+
+// Sender returns the user who triggered the interaction, whether it's in a
+// guild or in direct messages.
+func (i Interaction) Sender() User {
+	if i.Member != nil {
+		return i.Member.User
+	}
+	if i.User != nil {
+		return *i.User
+	}
+	return User{}
+}
+
+// InteractionData is the data of an interaction. Only the fields of the
+// interaction's type are filled.
+type InteractionData struct {
+	// Application command fields.
+	ID   Snowflake `json:"id,omitempty"`
+	Name string    `json:"name,omitempty"`
+
+	// Component fields. Values are the values of the picked options of a
+	// select menu.
+	CustomID      string        `json:"custom_id,omitempty"`
+	ComponentType ComponentType `json:"component_type,omitempty"`
+	Values        []string      `json:"values,omitempty"`
+}
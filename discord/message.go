@@ -1,5 +1,7 @@
 package discord
 
+import "github.com/diamondburned/arikawa/utils/json"
+
 type Message struct {
 	ID        Snowflake   `json:"id,string"`
 	Type      MessageType `json:"type"`
@@ -46,6 +48,14 @@ type Message struct {
 	Application *MessageApplication `json:"application,omitempty"`
 	Reference   *MessageReference   `json:"message_reference,omitempty"`
 	Flags       MessageFlags        `json:"flags"`
+
+	// Components are the action rows of buttons and select menus under the
+	// message.
+	Components []Component `json:"components,omitempty"`
+
+	// Extras holds the fields that the library doesn't know of yet. It's only
+	// filled if json.CaptureExtras is true.
+	Extras json.Extras `json:"-"`
 }
 
 // URL generates a Discord client URL to the message. If the message doesn't
@@ -89,6 +99,10 @@ const (
 	SuppressEmbeds
 	SourceMessageDeleted
 	UrgentMessage
+	_
+	// EphemeralMessage is only seen by the user who triggered an interaction.
+	// It can only be set on interaction responses.
+	EphemeralMessage
 )
 
 type ChannelMention struct {
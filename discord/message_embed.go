@@ -1,11 +1,59 @@
 package discord
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
 
 type Color uint32
 
 var DefaultEmbedColor Color = 0x303030
 
+// Discord's brand colors.
+const (
+	ColorBlurple Color = 0x5865F2
+	ColorGreen   Color = 0x57F287
+	ColorYellow  Color = 0xFEE75C
+	ColorFuchsia Color = 0xEB459E
+	ColorRed     Color = 0xED4245
+	ColorWhite   Color = 0xFFFFFF
+	ColorBlack   Color = 0x000000
+)
+
+// ColorFromRGB creates a Color from red, green, and blue.
+func ColorFromRGB(r, g, b uint8) Color {
+	return Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// ColorFromHex parses a hex color, such as "#5865F2", "5865F2" or the short
+// "#FFF".
+func ColorFromHex(hex string) (Color, error) {
+	var s = strings.TrimPrefix(hex, "#")
+
+	// Expand the short form, so "FFF" becomes "FFFFFF".
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+
+	if len(s) != 6 {
+		return 0, fmt.Errorf("invalid hex color %q", hex)
+	}
+
+	c, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hex color %q", hex)
+	}
+
+	return Color(c), nil
+}
+
+// String formats the color as a hex color, such as "#5865F2".
+func (c Color) String() string {
+	return fmt.Sprintf("#%06X", c.Uint32())
+}
+
 func (c Color) Uint32() uint32 {
 	return uint32(c)
 }
@@ -70,6 +118,8 @@ func (e ErrOverbound) Error() string {
 	return fmt.Sprintf(e.Thing+" overbound: %d > %d", e.Count, e.Max)
 }
 
+// Validate checks the embed against Discord's limits, which are counted in
+// characters. It also fills in the default type and color.
 func (e *Embed) Validate() error {
 	if e.Type == "" {
 		e.Type = NormalEmbed
@@ -79,59 +129,76 @@ func (e *Embed) Validate() error {
 		e.Color = DefaultEmbedColor
 	}
 
-	if len(e.Title) > 256 {
-		return &ErrOverbound{len(e.Title), 256, "Title"}
+	if runes(e.Title) > 256 {
+		return &ErrOverbound{runes(e.Title), 256, "Title"}
 	}
 
-	if len(e.Description) > 2048 {
-		return &ErrOverbound{len(e.Description), 2048, "Description"}
+	if runes(e.Description) > 2048 {
+		return &ErrOverbound{runes(e.Description), 2048, "Description"}
 	}
 
 	if len(e.Fields) > 25 {
 		return &ErrOverbound{len(e.Fields), 25, "Fields"}
 	}
 
-	var sum = 0 +
-		len(e.Title) +
-		len(e.Description)
-
 	if e.Footer != nil {
-		if len(e.Footer.Text) > 2048 {
-			return &ErrOverbound{len(e.Footer.Text), 2048, "Footer text"}
+		if runes(e.Footer.Text) > 2048 {
+			return &ErrOverbound{runes(e.Footer.Text), 2048, "Footer text"}
 		}
-
-		sum += len(e.Footer.Text)
 	}
 
 	if e.Author != nil {
-		if len(e.Author.Name) > 256 {
-			return &ErrOverbound{len(e.Author.Name), 256, "Author name"}
+		if runes(e.Author.Name) > 256 {
+			return &ErrOverbound{runes(e.Author.Name), 256, "Author name"}
 		}
-
-		sum += len(e.Author.Name)
 	}
 
 	for i, field := range e.Fields {
-		if len(field.Name) > 256 {
-			return &ErrOverbound{len(field.Name), 256,
+		if field.Name == "" || field.Value == "" {
+			return fmt.Errorf("field %d has an empty name or value", i)
+		}
+
+		if runes(field.Name) > 256 {
+			return &ErrOverbound{runes(field.Name), 256,
 				fmt.Sprintf("field %d name", i)}
 		}
 
-		if len(field.Value) > 1024 {
-			return &ErrOverbound{len(field.Value), 1024,
+		if runes(field.Value) > 1024 {
+			return &ErrOverbound{runes(field.Value), 1024,
 				fmt.Sprintf("field %d value", i)}
 		}
-
-		sum += len(field.Name) + len(field.Value)
 	}
 
-	if sum > 6000 {
+	if sum := e.Length(); sum > 6000 {
 		return &ErrOverbound{sum, 6000, "Sum of all characters"}
 	}
 
 	return nil
 }
 
+// Length returns the number of characters in the embed that count towards
+// Discord's limit of 6000, which applies to all embeds of a message combined.
+func (e *Embed) Length() int {
+	var sum = runes(e.Title) + runes(e.Description)
+
+	if e.Footer != nil {
+		sum += runes(e.Footer.Text)
+	}
+	if e.Author != nil {
+		sum += runes(e.Author.Name)
+	}
+
+	for _, field := range e.Fields {
+		sum += runes(field.Name) + runes(field.Value)
+	}
+
+	return sum
+}
+
+func runes(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
 type EmbedType string
 
 const (
@@ -0,0 +1,90 @@
+package discord
+
+// EmbedBuilder builds an embed with chained calls. Build validates the embed,
+// so going over Discord's limits is caught before the embed is sent:
+//
+//    embed, err := discord.NewEmbedBuilder().
+//        SetTitle("Stats").
+//        SetColor(discord.ColorBlurple).
+//        AddInlineField("Guilds", "42").
+//        AddInlineField("Users", "1337").
+//        Build()
+//
+type EmbedBuilder struct {
+	embed *Embed
+}
+
+// NewEmbedBuilder creates a builder around NewEmbed.
+func NewEmbedBuilder() *EmbedBuilder {
+	return &EmbedBuilder{NewEmbed()}
+}
+
+func (b *EmbedBuilder) SetTitle(title string) *EmbedBuilder {
+	b.embed.Title = title
+	return b
+}
+
+func (b *EmbedBuilder) SetDescription(description string) *EmbedBuilder {
+	b.embed.Description = description
+	return b
+}
+
+func (b *EmbedBuilder) SetURL(url URL) *EmbedBuilder {
+	b.embed.URL = url
+	return b
+}
+
+func (b *EmbedBuilder) SetColor(color Color) *EmbedBuilder {
+	b.embed.Color = color
+	return b
+}
+
+func (b *EmbedBuilder) SetTimestamp(timestamp Timestamp) *EmbedBuilder {
+	b.embed.Timestamp = timestamp
+	return b
+}
+
+func (b *EmbedBuilder) SetFooter(text string, icon URL) *EmbedBuilder {
+	b.embed.Footer = &EmbedFooter{Text: text, Icon: icon}
+	return b
+}
+
+func (b *EmbedBuilder) SetAuthor(name string, url, icon URL) *EmbedBuilder {
+	b.embed.Author = &EmbedAuthor{Name: name, URL: url, Icon: icon}
+	return b
+}
+
+func (b *EmbedBuilder) SetImage(url URL) *EmbedBuilder {
+	b.embed.Image = &EmbedImage{URL: url}
+	return b
+}
+
+func (b *EmbedBuilder) SetThumbnail(url URL) *EmbedBuilder {
+	b.embed.Thumbnail = &EmbedThumbnail{URL: url}
+	return b
+}
+
+func (b *EmbedBuilder) AddField(name, value string) *EmbedBuilder {
+	b.embed.Fields = append(b.embed.Fields, EmbedField{Name: name, Value: value})
+	return b
+}
+
+func (b *EmbedBuilder) AddInlineField(name, value string) *EmbedBuilder {
+	b.embed.Fields = append(b.embed.Fields, EmbedField{
+		Name:   name,
+		Value:  value,
+		Inline: true,
+	})
+	return b
+}
+
+// Validate validates the embed built so far. See (*Embed).Validate.
+func (b *EmbedBuilder) Validate() error {
+	return b.embed.Validate()
+}
+
+// Build validates and returns the embed. The embed is returned even if it's
+// invalid.
+func (b *EmbedBuilder) Build() (*Embed, error) {
+	return b.embed, b.embed.Validate()
+}
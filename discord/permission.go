@@ -87,7 +87,8 @@ const (
 		PermissionDeafenMembers |
 		PermissionMoveMembers |
 		PermissionUseVAD |
-		PermissionPrioritySpeaker
+		PermissionPrioritySpeaker |
+		PermissionStream
 
 	PermissionAllChannel = 0 |
 		PermissionAllText |
@@ -118,7 +119,10 @@ func (p Permissions) Add(perm Permissions) Permissions {
 	return p | perm
 }
 
-func CalcOverwrites(guild Guild, channel Channel, member Member) Permissions {
+// CalcPermissions calculates the member's permissions in the guild from the
+// @everyone role and the member's roles, without any channel overwrites. The
+// guild owner and administrators have all permissions.
+func CalcPermissions(guild Guild, member Member) Permissions {
 	if guild.OwnerID == member.User.ID {
 		return PermissionAll
 	}
@@ -126,13 +130,12 @@ func CalcOverwrites(guild Guild, channel Channel, member Member) Permissions {
 	var perm Permissions
 
 	for _, role := range guild.Roles {
+		// The @everyone role has the same ID as the guild.
 		if role.ID == guild.ID {
 			perm |= role.Permissions
-			break
+			continue
 		}
-	}
 
-	for _, role := range guild.Roles {
 		for _, id := range member.RoleIDs {
 			if id == role.ID {
 				perm |= role.Permissions
@@ -145,6 +148,24 @@ func CalcOverwrites(guild Guild, channel Channel, member Member) Permissions {
 		return PermissionAll
 	}
 
+	return perm
+}
+
+// CalcOverwrites calculates the member's permissions in the channel. The
+// overwrites are applied on top of CalcPermissions in the order Discord
+// documents:
+//
+//    1. The @everyone overwrite.
+//    2. All of the member's role overwrites, denies before allows.
+//    3. The member's own overwrite.
+//
+// Administrators bypass the overwrites.
+func CalcOverwrites(guild Guild, channel Channel, member Member) Permissions {
+	var perm = CalcPermissions(guild, member)
+	if perm == PermissionAll {
+		return perm
+	}
+
 	for _, overwrite := range channel.Permissions {
 		if overwrite.ID == guild.ID {
 			perm &= ^overwrite.Deny
@@ -156,8 +177,12 @@ func CalcOverwrites(guild Guild, channel Channel, member Member) Permissions {
 	var deny, allow Permissions
 
 	for _, overwrite := range channel.Permissions {
+		if overwrite.Type != OverwriteRole {
+			continue
+		}
+
 		for _, id := range member.RoleIDs {
-			if id == overwrite.ID && overwrite.Type == "role" {
+			if id == overwrite.ID {
 				deny |= overwrite.Deny
 				allow |= overwrite.Allow
 				break
@@ -169,16 +194,12 @@ func CalcOverwrites(guild Guild, channel Channel, member Member) Permissions {
 	perm |= allow
 
 	for _, overwrite := range channel.Permissions {
-		if overwrite.ID == member.User.ID {
+		if overwrite.ID == member.User.ID && overwrite.Type == OverwriteMember {
 			perm &= ^overwrite.Deny
 			perm |= overwrite.Allow
 			break
 		}
 	}
 
-	if perm.Has(PermissionAdministrator) {
-		return PermissionAll
-	}
-
 	return perm
 }
@@ -6,14 +6,28 @@ import (
 	"time"
 )
 
+// DiscordEpoch is the first second of 2015 in Unix nanoseconds. Snowflake
+// timestamps are milliseconds since this epoch.
 const DiscordEpoch = 1420070400000 * int64(time.Millisecond)
 
 type Snowflake int64
 
-func NewSnowflake(t time.Time) Snowflake {
+// NewSnowflakeFromTime creates a snowflake with the given creation time and all
+// other bits zeroed. Every ID created at or after t is at least this snowflake,
+// so it can be used as a boundary for Before and After queries:
+//
+//    // Messages sent in the last hour.
+//    after := discord.NewSnowflakeFromTime(time.Now().Add(-time.Hour))
+//
+func NewSnowflakeFromTime(t time.Time) Snowflake {
 	return Snowflake(TimeToDiscordEpoch(t) << 22)
 }
 
+// NewSnowflake is an alias of NewSnowflakeFromTime.
+func NewSnowflake(t time.Time) Snowflake {
+	return NewSnowflakeFromTime(t)
+}
+
 func ParseSnowflake(sf string) (Snowflake, error) {
 	i, err := strconv.ParseInt(sf, 10, 64)
 	if err != nil {
@@ -23,6 +37,7 @@ func ParseSnowflake(sf string) (Snowflake, error) {
 	return Snowflake(i), nil
 }
 
+// UnmarshalJSON unmarshals the snowflake from either a string or a number.
 func (s *Snowflake) UnmarshalJSON(v []byte) error {
 	id := strings.Trim(string(v), `"`)
 	if id == "null" {
@@ -38,10 +53,13 @@ func (s *Snowflake) UnmarshalJSON(v []byte) error {
 	return nil
 }
 
-func (s *Snowflake) MarshalJSON() ([]byte, error) {
+// MarshalJSON marshals the snowflake as a quoted string, as JavaScript numbers
+// can't hold more than 53 bits. A value receiver is used so snowflakes in
+// structs that aren't addressable are still quoted.
+func (s Snowflake) MarshalJSON() ([]byte, error) {
 	var id string
 
-	switch i := int64(*s); i {
+	switch i := int64(s); i {
 	case -1: // @me
 		id = "@me"
 	case 0:
@@ -61,12 +79,13 @@ func (s Snowflake) Valid() bool {
 	return uint64(s) > 0
 }
 
+// Time returns the time the snowflake was created, with millisecond precision.
 func (s Snowflake) Time() time.Time {
-	return time.Unix(0, int64(s)>>22*1000000+DiscordEpoch)
+	return time.Unix(0, int64(s)>>22*int64(time.Millisecond)+DiscordEpoch)
 }
 
 func (s Snowflake) Worker() uint8 {
-	return uint8(s & 0x3E0000)
+	return uint8(s & 0x3E0000 >> 17)
 }
 
 func (s Snowflake) PID() uint8 {
@@ -77,6 +96,7 @@ func (s Snowflake) Increment() uint16 {
 	return uint16(s & 0xFFF)
 }
 
+// TimeToDiscordEpoch returns the milliseconds between DiscordEpoch and t.
 func TimeToDiscordEpoch(t time.Time) int64 {
-	return t.UnixNano()/int64(time.Millisecond) - DiscordEpoch
+	return (t.UnixNano() - DiscordEpoch) / int64(time.Millisecond)
 }
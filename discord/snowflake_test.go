@@ -0,0 +1,84 @@
+package discord
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSnowflakeMarshal(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   interface{}
+		out  string
+	}{
+		{"value", Snowflake(1 << 53), `"9007199254740992"`},
+		{"value above 53 bits", Snowflake(1<<53 + 1), `"9007199254740993"`},
+		{"pointer", func() *Snowflake { s := Snowflake(42); return &s }(), `"42"`},
+		{"struct field", struct{ ID Snowflake }{1<<53 + 1}, `{"ID":"9007199254740993"}`},
+		{"me", Snowflake(-1), `"@me"`},
+		{"zero", Snowflake(0), `null`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(test.in)
+			if err != nil {
+				t.Fatal("Failed to marshal:", err)
+			}
+
+			if string(b) != test.out {
+				t.Fatalf("Unexpected JSON: %s, expected %s", b, test.out)
+			}
+		})
+	}
+}
+
+func TestSnowflakeUnmarshal(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out Snowflake
+	}{
+		{`"9007199254740993"`, 1<<53 + 1},
+		{`9007199254740993`, 1<<53 + 1},
+		{`"175928847299117063"`, 175928847299117063},
+		{`175928847299117063`, 175928847299117063},
+		{`null`, 0},
+	}
+
+	for _, test := range tests {
+		var s Snowflake
+		if err := json.Unmarshal([]byte(test.in), &s); err != nil {
+			t.Fatalf("Failed to unmarshal %s: %v", test.in, err)
+		}
+
+		if s != test.out {
+			t.Fatalf("Unexpected snowflake from %s: %d, expected %d", test.in, s, test.out)
+		}
+	}
+}
+
+func TestSnowflakeRoundTrip(t *testing.T) {
+	var in = struct {
+		IDs []Snowflake `json:"ids"`
+	}{
+		IDs: []Snowflake{1<<53 - 1, 1 << 53, 1<<53 + 1, 1<<63 - 1},
+	}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal("Failed to marshal:", err)
+	}
+
+	var out = in
+	out.IDs = nil
+
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal("Failed to unmarshal:", err)
+	}
+
+	for i := range in.IDs {
+		if in.IDs[i] != out.IDs[i] {
+			t.Fatalf("Lost precision: %d became %d", in.IDs[i], out.IDs[i])
+		}
+	}
+}
@@ -2,6 +2,8 @@ package discord
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -77,6 +79,68 @@ func (t UnixTimestamp) Time() time.Time {
 
 //
 
+// TimestampStyle is how a timestamp tag is shown. Each user sees it in their
+// own time zone and locale.
+type TimestampStyle string
+
+const (
+	DefaultTimestamp TimestampStyle = ""  // same as ShortDateTime
+	ShortTime        TimestampStyle = "t" // 16:20
+	LongTime         TimestampStyle = "T" // 16:20:30
+	ShortDate        TimestampStyle = "d" // 20/04/2021
+	LongDate         TimestampStyle = "D" // 20 April 2021
+	ShortDateTime    TimestampStyle = "f" // 20 April 2021 16:20
+	LongDateTime     TimestampStyle = "F" // Tuesday, 20 April 2021 16:20
+	RelativeTime     TimestampStyle = "R" // 2 months ago
+)
+
+// Valid returns whether the style is one that Discord knows.
+func (s TimestampStyle) Valid() bool {
+	switch s {
+	case DefaultTimestamp, ShortTime, LongTime, ShortDate, LongDate,
+		ShortDateTime, LongDateTime, RelativeTime:
+		return true
+	}
+	return false
+}
+
+// FormatTimestamp returns a timestamp tag, such as <t:1618932630:R>, which
+// Discord shows as the time in the given style, e.g. "in 2 hours".
+func FormatTimestamp(t time.Time, style TimestampStyle) string {
+	var tag = "<t:" + strconv.FormatInt(t.Unix(), 10)
+	if style != DefaultTimestamp {
+		tag += ":" + string(style)
+	}
+	return tag + ">"
+}
+
+// ParseTimestamp parses a timestamp tag made by FormatTimestamp, returning its
+// time and style.
+func ParseTimestamp(tag string) (time.Time, TimestampStyle, error) {
+	if !strings.HasPrefix(tag, "<t:") || !strings.HasSuffix(tag, ">") {
+		return time.Time{}, "", fmt.Errorf("%q is not a timestamp tag", tag)
+	}
+
+	var parts = strings.SplitN(tag[3:len(tag)-1], ":", 2)
+
+	unix, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid time in timestamp tag %q", tag)
+	}
+
+	var style = DefaultTimestamp
+	if len(parts) == 2 {
+		style = TimestampStyle(parts[1])
+		if style == DefaultTimestamp || !style.Valid() {
+			return time.Time{}, "", fmt.Errorf("invalid style in timestamp tag %q", tag)
+		}
+	}
+
+	return time.Unix(unix, 0), style, nil
+}
+
+//
+
 type UnixMsTimestamp int64
 
 func TimeToMilliseconds(t time.Time) UnixMsTimestamp {
@@ -0,0 +1,39 @@
+package discord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestampTag(t *testing.T) {
+	var now = time.Unix(1618932630, 0)
+
+	var tests = []struct {
+		style TimestampStyle
+		tag   string
+	}{
+		{DefaultTimestamp, "<t:1618932630>"},
+		{RelativeTime, "<t:1618932630:R>"},
+		{LongDate, "<t:1618932630:D>"},
+	}
+
+	for _, test := range tests {
+		if tag := FormatTimestamp(now, test.style); tag != test.tag {
+			t.Fatalf("Unexpected tag for %q: %s", test.style, tag)
+		}
+
+		parsed, style, err := ParseTimestamp(test.tag)
+		if err != nil {
+			t.Fatal("Failed to parse:", err)
+		}
+		if !parsed.Equal(now) || style != test.style {
+			t.Fatal("Unexpected parse of", test.tag, parsed, style)
+		}
+	}
+
+	for _, invalid := range []string{"<t:>", "<t:1618932630:x>", "<t:1618932630:>", "1618932630", "<@1>"} {
+		if _, _, err := ParseTimestamp(invalid); err == nil {
+			t.Fatal("Expected error for", invalid)
+		}
+	}
+}
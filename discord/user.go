@@ -1,6 +1,10 @@
 package discord
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/diamondburned/arikawa/utils/json"
+)
 
 // DefaultAvatarURL is the link to the default green avatar on Discord. It's
 // returned from AvatarURL() if the user doesn't have an avatar.
@@ -26,6 +30,11 @@ type User struct {
 	Flags       UserFlags `json:"flags,omitempty"`
 	PublicFlags UserFlags `json:"public_flags,omitempty"`
 	Nitro       UserNitro `json:"premium_type,omitempty"`
+
+	// Extras holds the fields that the library doesn't know of yet. It's only
+	// filled if json.CaptureExtras is true, and is nil if there are none. It's
+	// a pointer to keep User comparable.
+	Extras *json.Extras `json:"-"`
 }
 
 func (u User) Mention() string {
@@ -19,7 +19,12 @@ func (g *Gateway) Identify() error {
 		return errors.Wrap(err, "Can't wait for identify()")
 	}
 
-	return g.Send(IdentifyOP, g.Identifier)
+	// Copy the data, as UpdateStatus may change the presence meanwhile.
+	g.presenceMutex.Lock()
+	var data = g.Identifier.IdentifyData
+	g.presenceMutex.Unlock()
+
+	return g.Send(IdentifyOP, data)
 }
 
 type ResumeData struct {
@@ -61,6 +66,10 @@ type RequestGuildMembersData struct {
 	Query     string `json:"query,omitempty"`
 	Limit     uint   `json:"limit"`
 	Presences bool   `json:"presences,omitempty"`
+
+	// Nonce is sent back in each GuildMembersChunkEvent. It can be up to 32
+	// bytes long.
+	Nonce string `json:"nonce,omitempty"`
 }
 
 func (g *Gateway) RequestGuildMembers(data RequestGuildMembersData) error {
@@ -89,7 +98,14 @@ type UpdateStatusData struct {
 	AFK    bool           `json:"afk"`
 }
 
+// UpdateStatus updates the presence of the current user. The presence is also
+// kept as the Identifier's Presence, so it's restored when the Gateway has to
+// identify again after a reconnection.
 func (g *Gateway) UpdateStatus(data UpdateStatusData) error {
+	g.presenceMutex.Lock()
+	g.Identifier.Presence = &data
+	g.presenceMutex.Unlock()
+
 	return g.Send(StatusUpdateOP, data)
 }
 
@@ -0,0 +1,37 @@
+package gateway
+
+import "github.com/diamondburned/arikawa/utils/wsutil"
+
+// Compression is the kind of compression that the Gateway asks Discord for.
+// Compressed payloads are inflated by the Websocket connection, so events are
+// the same either way.
+type Compression uint8
+
+const (
+	// NoCompression sends all payloads as they are. This costs the least CPU,
+	// but the most bandwidth.
+	NoCompression Compression = iota
+	// PayloadCompression sets the compress field of Identify, so that Discord
+	// compresses large payloads, such as Ready and big GuildCreates, one at a
+	// time. Each one is inflated on its own, so nothing is kept between
+	// payloads, but small payloads aren't compressed at all. This is the
+	// default.
+	PayloadCompression
+	// StreamCompression uses zlib-stream transport compression, which
+	// compresses all payloads with a single zlib context for the whole
+	// connection. Payloads refer back to earlier ones, so this saves a lot
+	// more bandwidth for busy shards, at the cost of keeping a 32KB window
+	// per connection and inflating every payload.
+	StreamCompression
+)
+
+// SetCompression sets the kind of compression, overriding Compress. This must
+// be called before opening the Gateway. StreamCompression only works if WS
+// uses wsutil.Conn, which is the default.
+func (g *Gateway) SetCompression(c Compression) {
+	g.Identifier.Compress = c == PayloadCompression
+
+	if conn, ok := g.WS.Conn.(*wsutil.Conn); ok {
+		conn.Compress = c == StreamCompression
+	}
+}
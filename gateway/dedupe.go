@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"sync"
+
+	"github.com/diamondburned/arikawa/discord"
+)
+
+// DedupeSize is how many of the latest message IDs are remembered for
+// DedupeMessages. Replayed events are at most a few minutes old, so this only
+// needs to cover the messages received in that time. A size of 0 or less
+// remembers nothing, which disables deduplication.
+var DedupeSize = 1000
+
+// recentIDs remembers the latest DedupeSize IDs it's given.
+type recentIDs struct {
+	mu   sync.Mutex
+	ids  map[discord.Snowflake]struct{}
+	ring []discord.Snowflake
+	next int
+}
+
+// seen returns whether the ID was already given, adding it if it wasn't. The
+// oldest ID is forgotten once DedupeSize IDs are remembered.
+func (r *recentIDs) seen(id discord.Snowflake) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.ids[id]; ok {
+		return true
+	}
+
+	if r.ids == nil {
+		if DedupeSize <= 0 {
+			return false
+		}
+
+		r.ids = make(map[discord.Snowflake]struct{}, DedupeSize)
+		r.ring = make([]discord.Snowflake, DedupeSize)
+	}
+
+	if old := r.ring[r.next]; old.Valid() {
+		delete(r.ids, old)
+	}
+
+	r.ids[id] = struct{}{}
+	r.ring[r.next] = id
+	r.next = (r.next + 1) % len(r.ring)
+
+	return false
+}
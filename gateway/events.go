@@ -1,6 +1,9 @@
 package gateway
 
-import "github.com/diamondburned/arikawa/discord"
+import (
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/utils/json"
+)
 
 // Rules: VOICE_STATE_UPDATE -> VoiceStateUpdateEvent
 
@@ -99,6 +102,13 @@ type (
 		GuildID discord.Snowflake `json:"guild_id"`
 		Members []discord.Member  `json:"members"`
 
+		// ChunkIndex starts at 0 and is less than ChunkCount.
+		ChunkIndex int `json:"chunk_index"`
+		ChunkCount int `json:"chunk_count"`
+
+		// Nonce is the nonce given in RequestGuildMembersData.
+		Nonce string `json:"nonce,omitempty"`
+
 		// Whatever's not found goes here
 		NotFound []string `json:"not_found,omitempty"`
 
@@ -273,6 +283,19 @@ type (
 	}
 )
 
+// https://discord.com/developers/docs/topics/gateway#interactions
+type (
+	// InteractionCreateEvent is sent when a user uses an application command
+	// or a message component. It needs no intents.
+	InteractionCreateEvent discord.Interaction
+)
+
+// Sender returns the user who triggered the interaction, whether it's in a
+// guild or in direct messages.
+func (i InteractionCreateEvent) Sender() discord.User {
+	return discord.Interaction(i).Sender()
+}
+
 // https://discordapp.com/developers/docs/topics/gateway#webhooks
 type (
 	WebhooksUpdateEvent struct {
@@ -295,3 +318,13 @@ type (
 	RelationshipAdd    Relationship
 	RelationshipRemove Relationship
 )
+
+// RawEvent is sent for dispatched events that the library doesn't know of yet,
+// such as newly added ones, instead of dropping them. Known events are never
+// sent as RawEvents. The intents they need aren't known either, so they're
+// missing from EventIntents.
+type RawEvent struct {
+	// Type is the event name, such as "GUILD_CREATE".
+	Type string
+	Data json.Raw
+}
@@ -70,6 +70,8 @@ var EventCreator = map[string]func() Event{
 
 	"WEBHOOKS_UPDATE": func() Event { return new(WebhooksUpdateEvent) },
 
+	"INTERACTION_CREATE": func() Event { return new(InteractionCreateEvent) },
+
 	"USER_UPDATE": func() Event {
 		return new(UserUpdateEvent)
 	},
@@ -9,6 +9,7 @@ package gateway
 
 import (
 	"context"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"sync"
@@ -28,12 +29,23 @@ var (
 
 	Version  = "6"
 	Encoding = "json"
-	// Compress = "zlib-stream"
+	// Compress is the kind of compression that new Gateways use. Refer to
+	// Compression for the tradeoffs.
+	Compress = PayloadCompression
 )
 
 var (
 	ErrMissingForResume = errors.New("missing session ID or sequence for resuming")
 	ErrWSMaxTries       = errors.New("max tries reached")
+	ErrMissedAck        = errors.New("heartbeat was not acknowledged")
+)
+
+var (
+	// ReconnectDelay is the delay after the first failed reconnection attempt.
+	// It's doubled after each failed attempt, up to ReconnectMaxDelay.
+	ReconnectDelay = time.Second
+	// ReconnectMaxDelay is the maximum delay between reconnection attempts.
+	ReconnectMaxDelay = 2 * time.Minute
 )
 
 // GatewayBotData contains the GatewayURL as well as extra metadata on how to
@@ -93,6 +105,21 @@ type Gateway struct {
 
 	ErrorLog func(err error) // default to log.Println
 
+	// OnMissedAck is called when a heartbeat isn't acknowledged before the next
+	// one is due. If nil, ErrMissedAck is given to ErrorLog instead. The
+	// connection is then considered dead and reconnected.
+	OnMissedAck func()
+
+	// DedupeMessages drops MessageCreateEvents of messages that were already
+	// received, such as the ones Discord replays after a Resume, so commands
+	// aren't run twice. The latest DedupeSize messages are remembered. It's
+	// true by default.
+	DedupeMessages bool
+
+	// ReconnectTries is the maximum number of attempts for each reconnection,
+	// after which ErrWSMaxTries is returned. 0 means trying forever.
+	ReconnectTries int
+
 	// AfterClose is called after each close. Error can be non-nil, as this is
 	// called even when the Gateway is gracefully closed. It's used mainly for
 	// reconnections or any type of connection interruptions.
@@ -105,14 +132,28 @@ type Gateway struct {
 
 	// Filled by methods, internal use
 	waitGroup *sync.WaitGroup
+	messages  recentIDs
+
+	// presenceMutex guards the Identifier's Presence, which UpdateStatus sets.
+	presenceMutex sync.Mutex
 }
 
 // NewGateway starts a new Gateway with the default stdlib JSON driver. For more
 // information, refer to NewGatewayWithDriver.
 func NewGateway(token string) (*Gateway, error) {
+	URL, err := gatewayURL()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCustomGateway(URL, token), nil
+}
+
+// gatewayURL returns the Gateway URL with the version and encoding parameters.
+func gatewayURL() (string, error) {
 	URL, err := URL()
 	if err != nil {
-		return nil, errors.Wrap(err, "Failed to get gateway endpoint")
+		return "", errors.Wrap(err, "Failed to get gateway endpoint")
 	}
 
 	// Parameters for the gateway
@@ -122,13 +163,11 @@ func NewGateway(token string) (*Gateway, error) {
 	}
 
 	// Append the form to the URL
-	URL += "?" + param.Encode()
-
-	return NewCustomGateway(URL, token), nil
+	return URL + "?" + param.Encode(), nil
 }
 
 func NewCustomGateway(gatewayURL, token string) *Gateway {
-	return &Gateway{
+	g := &Gateway{
 		WS:        wsutil.NewCustom(wsutil.NewConn(), gatewayURL),
 		WSTimeout: wsutil.WSTimeout,
 
@@ -136,9 +175,21 @@ func NewCustomGateway(gatewayURL, token string) *Gateway {
 		Identifier: DefaultIdentifier(token),
 		Sequence:   NewSequence(),
 
+		DedupeMessages: true,
+
 		ErrorLog:   wsutil.WSError,
 		AfterClose: func(error) {},
 	}
+
+	g.SetCompression(Compress)
+	return g
+}
+
+// AddIntent adds the intents to the Identify payload. Once any intent is added,
+// only the events of the added intents are received. This must be called before
+// opening the Gateway.
+func (g *Gateway) AddIntent(i Intents) {
+	g.Identifier.Intents = g.Identifier.Intents.Add(i)
 }
 
 // Close closes the underlying Websocket connection.
@@ -179,8 +230,10 @@ func (g *Gateway) Close() error {
 	return err
 }
 
-// Reconnect tries to reconnect forever. It will resume the connection if
-// possible. If an Invalid Session is received, it will start a fresh one.
+// Reconnect tries to reconnect until ReconnectTries is reached, waiting
+// longer after each failed attempt. It will resume the connection if possible.
+// If resuming fails or an Invalid Session is received, it will start a fresh
+// one. Either a Ready or a Resumed event is sent into Events once reconnected.
 func (g *Gateway) Reconnect() error {
 	return g.ReconnectContext(context.Background())
 }
@@ -199,14 +252,58 @@ func (g *Gateway) ReconnectContext(ctx context.Context) error {
 		// If the connection is rate limited (documented behavior):
 		// https://discordapp.com/developers/docs/topics/gateway#rate-limiting
 
-		if err := g.OpenContext(ctx); err != nil {
-			g.ErrorLog(errors.Wrap(err, "Failed to open gateway"))
-			continue
+		if err := g.WS.Dial(ctx); err != nil {
+			g.ErrorLog(errors.Wrap(err, "Failed to reconnect"))
+
+		} else if err := g.Start(); err != nil {
+			g.ErrorLog(errors.Wrap(err, "Failed to start gateway"))
+
+			// The session might not be resumable anymore, so identify with a
+			// new session the next time.
+			if g.SessionID != "" {
+				wsutil.WSDebug("Dropping the session after a failed resume.")
+				g.SessionID = ""
+				g.Sequence.Set(0)
+			}
+
+		} else {
+			wsutil.WSDebug("Started after attempt:", i)
+			return nil
 		}
 
-		wsutil.WSDebug("Started after attempt:", i)
-		return nil
+		if g.ReconnectTries > 0 && i >= g.ReconnectTries {
+			return ErrWSMaxTries
+		}
+
+		delay := backoff(i)
+		wsutil.WSDebug("Retrying after", delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// backoff returns the delay after the given failed attempt, which starts at 1.
+// The delay is randomized between half and all of the exponential delay, so
+// that shards don't all reconnect at once.
+func backoff(attempt int) time.Duration {
+	var delay = ReconnectDelay
+	for i := 1; i < attempt && delay < ReconnectMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > ReconnectMaxDelay {
+		delay = ReconnectMaxDelay
 	}
+
+	if delay < 2 {
+		return delay
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)))
 }
 
 // Open connects to the Websocket and authenticate it. You should usually use
@@ -298,8 +395,13 @@ func (g *Gateway) start() error {
 		return errors.Wrap(err, "First error")
 	}
 
-	// Use the pacemaker loop.
+	// Use the pacemaker loop. As Discord asks, the first heartbeat is jittered,
+	// and the connection is reconnected if a heartbeat isn't acknowledged
+	// before the next one, as it's probably dead.
 	g.PacerLoop = wsutil.NewLoop(hello.HeartbeatInterval.Duration(), ch, g)
+	g.PacerLoop.SetMissed(g.missedAck)
+	g.PacerLoop.SetJitter(true)
+	g.PacerLoop.SetStrictEcho(true)
 
 	// Start the event handler, which also handles the pacemaker death signal.
 	g.waitGroup.Add(1)
@@ -310,7 +412,10 @@ func (g *Gateway) start() error {
 
 		if err != nil {
 			g.ErrorLog(err)
-			g.Reconnect()
+
+			if err := g.Reconnect(); err != nil {
+				g.ErrorLog(errors.Wrap(err, "Failed to reconnect"))
+			}
 		}
 	})
 
@@ -319,6 +424,22 @@ func (g *Gateway) start() error {
 	return nil
 }
 
+// Latency returns the time between the last heartbeat and its acknowledgement.
+// It returns 0 if the Gateway isn't started or no heartbeats are acknowledged
+// yet.
+func (g *Gateway) Latency() time.Duration {
+	return g.PacerLoop.Latency()
+}
+
+func (g *Gateway) missedAck() {
+	if g.OnMissedAck != nil {
+		g.OnMissedAck()
+		return
+	}
+
+	g.ErrorLog(ErrMissedAck)
+}
+
 func (g *Gateway) Send(code OPCode, v interface{}) error {
 	var op = wsutil.OP{
 		Code: code,
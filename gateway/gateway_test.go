@@ -0,0 +1,338 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/utils/json"
+	"github.com/diamondburned/arikawa/utils/wsutil"
+	"golang.org/x/time/rate"
+)
+
+func TestBackoff(t *testing.T) {
+	var tests = []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, ReconnectDelay},
+		{2, 2 * ReconnectDelay},
+		{3, 4 * ReconnectDelay},
+		{100, ReconnectMaxDelay},
+	}
+
+	for _, test := range tests {
+		d := backoff(test.attempt)
+		if d < test.max/2 || d > test.max {
+			t.Errorf("Attempt %d: delay %v not within [%v, %v]",
+				test.attempt, d, test.max/2, test.max)
+		}
+	}
+}
+
+func TestShardManager(t *testing.T) {
+	m := NewCustomShardManager("wss://localhost", "token", 3)
+
+	for i, g := range m.Gateways {
+		if g.Events != m.Events {
+			t.Fatal("Shard", i, "has its own Events channel")
+		}
+		if g.Identifier.Shard.ShardID() != i || g.Identifier.Shard.NumShards() != 3 {
+			t.Fatal("Unexpected shard:", *g.Identifier.Shard)
+		}
+		if g.Identifier.IdentifyShortLimit != m.Gateways[0].Identifier.IdentifyShortLimit {
+			t.Fatal("Shard", i, "has its own Identify limiter")
+		}
+	}
+
+	// (guildID >> 22) % 3
+	if g := m.FromGuildID(5 << 22); g != m.Gateways[2] {
+		t.Fatal("Unexpected shard for the guild:", *g.Identifier.Shard)
+	}
+}
+
+// sentConn is a wsutil.Connection that keeps the payloads sent to it.
+type sentConn struct {
+	sent []string
+}
+
+func (c *sentConn) Dial(context.Context, string) error { return nil }
+func (c *sentConn) Listen() <-chan wsutil.Event        { return nil }
+func (c *sentConn) Close() error                       { return nil }
+
+func (c *sentConn) Send(_ context.Context, b []byte) error {
+	c.sent = append(c.sent, string(b))
+	return nil
+}
+
+func TestUpdateStatus(t *testing.T) {
+	m := NewCustomShardManager("wss://localhost", "token", 2)
+
+	var conns []*sentConn
+	for _, g := range m.Gateways {
+		conn := &sentConn{}
+		g.WS.Conn = conn
+		conns = append(conns, conn)
+	}
+
+	err := m.UpdateStatus(UpdateStatusData{
+		Game:   &discord.Activity{Name: "tests"},
+		Status: discord.IdleStatus,
+	})
+	if err != nil {
+		t.Fatal("Failed to update status:", err)
+	}
+
+	for i, conn := range conns {
+		if len(conn.sent) != 1 || !strings.Contains(conn.sent[0], `"status":"idle"`) {
+			t.Fatal("Unexpected payloads on shard", i, conn.sent)
+		}
+	}
+
+	// The status is restored when identifying again.
+	if err := m.Gateways[1].Identify(); err != nil {
+		t.Fatal("Failed to identify:", err)
+	}
+
+	const presence = `"presence":{"since":0,"game":{"name":"tests","type":0},"status":"idle","afk":false}`
+	if sent := conns[1].sent; len(sent) != 2 || !strings.Contains(sent[1], presence) {
+		t.Fatal("Unexpected identify payload:", sent)
+	}
+}
+
+func TestSetCompression(t *testing.T) {
+	g := NewCustomGateway("wss://localhost", "token")
+	conn := g.WS.Conn.(*wsutil.Conn)
+
+	if !g.Identifier.Compress || conn.Compress {
+		t.Fatal("Expected payload compression by default")
+	}
+
+	g.SetCompression(StreamCompression)
+	if g.Identifier.Compress || !conn.Compress {
+		t.Fatal("Expected stream compression only")
+	}
+
+	g.SetCompression(NoCompression)
+	if g.Identifier.Compress || conn.Compress {
+		t.Fatal("Expected no compression")
+	}
+}
+
+// silentConn is a wsutil.Connection that says hello, but doesn't acknowledge
+// heartbeats until it's dialed again.
+type silentConn struct {
+	events chan wsutil.Event
+	dials  int
+	// beats receives the acknowledged heartbeats.
+	beats chan struct{}
+}
+
+func (c *silentConn) Listen() <-chan wsutil.Event { return c.events }
+func (c *silentConn) Close() error                { return nil }
+
+func (c *silentConn) Dial(context.Context, string) error {
+	c.dials++
+	c.events = make(chan wsutil.Event, 10)
+	c.events <- wsutil.Event{Data: []byte(`{"op":10,"d":{"heartbeat_interval":20}}`)}
+	c.events <- wsutil.Event{Data: []byte(`{"op":0,"t":"RESUMED","s":1,"d":{}}`)}
+	return nil
+}
+
+func (c *silentConn) Send(_ context.Context, b []byte) error {
+	if c.dials > 1 && strings.HasPrefix(string(b), `{"op":1,`) {
+		c.events <- wsutil.Event{Data: []byte(`{"op":11}`)}
+
+		select {
+		case c.beats <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func TestMissedAckReconnect(t *testing.T) {
+	var conn = &silentConn{beats: make(chan struct{}, 1)}
+
+	g := NewCustomGateway("wss://localhost", "token")
+	g.WS.Conn = conn
+	g.WS.DialLimiter = rate.NewLimiter(rate.Inf, 1)
+	g.Identifier.IdentifyShortLimit = rate.NewLimiter(rate.Inf, 1)
+	g.ErrorLog = func(error) {}
+
+	var missed = make(chan struct{}, 1)
+	g.OnMissedAck = func() { missed <- struct{}{} }
+
+	if err := g.Open(); err != nil {
+		t.Fatal("Failed to open:", err)
+	}
+
+	select {
+	case <-missed:
+	case <-time.After(time.Second):
+		t.Fatal("Missed heartbeat acknowledgement not noticed")
+	}
+
+	// The Gateway reconnects and heartbeats again.
+	select {
+	case <-conn.beats:
+	case <-time.After(time.Second):
+		t.Fatal("Gateway did not reconnect")
+	}
+
+	if err := g.Close(); err != nil {
+		t.Fatal("Failed to close:", err)
+	}
+
+	if conn.dials != 2 {
+		t.Fatal("Unexpected dial count:", conn.dials)
+	}
+}
+
+func TestDedupeMessages(t *testing.T) {
+	g := NewCustomGateway("wss://localhost", "token")
+
+	dispatch := func(seq int64, id string) {
+		err := g.HandleOP(&wsutil.OP{
+			Code:      DispatchOP,
+			Sequence:  seq,
+			EventName: "MESSAGE_CREATE",
+			Data:      json.Raw(`{"id":"` + id + `","content":"~ping"}`),
+		})
+		if err != nil {
+			t.Fatal("Failed to handle OP:", err)
+		}
+	}
+
+	dispatch(1, "1")
+	dispatch(2, "2")
+	dispatch(3, "1") // replayed after a Resume
+
+	if len(g.Events) != 2 {
+		t.Fatal("Unexpected event count:", len(g.Events))
+	}
+
+	g.DedupeMessages = false
+	dispatch(4, "1")
+
+	if len(g.Events) != 3 {
+		t.Fatal("Duplicate dropped while disabled")
+	}
+}
+
+func TestRawEvent(t *testing.T) {
+	g := NewCustomGateway("wss://localhost", "token")
+
+	err := g.HandleOP(&wsutil.OP{
+		Code:      DispatchOP,
+		Sequence:  1,
+		EventName: "NEW_FEATURE",
+		Data:      json.Raw(`{"id":"1"}`),
+	})
+	if err != nil {
+		t.Fatal("Failed to handle OP:", err)
+	}
+
+	raw, ok := (<-g.Events).(*RawEvent)
+	if !ok || raw.Type != "NEW_FEATURE" || string(raw.Data) != `{"id":"1"}` {
+		t.Fatal("Unexpected raw event:", raw)
+	}
+	if seq := g.Sequence.Get(); seq != 1 {
+		t.Fatal("Unexpected sequence:", seq)
+	}
+
+	// Known events are still typed.
+	err = g.HandleOP(&wsutil.OP{
+		Code:      DispatchOP,
+		EventName: "RESUMED",
+		Data:      json.Raw(`{}`),
+	})
+	if err != nil {
+		t.Fatal("Failed to handle OP:", err)
+	}
+
+	if ev, ok := (<-g.Events).(*ResumedEvent); !ok {
+		t.Fatal("Unexpected event:", ev)
+	}
+}
+
+func TestRecentIDs(t *testing.T) {
+	old := DedupeSize
+	DedupeSize = 2
+	defer func() { DedupeSize = old }()
+
+	var r recentIDs
+
+	for _, id := range []discord.Snowflake{1, 2, 3} {
+		if r.seen(id) {
+			t.Fatal("New ID seen:", id)
+		}
+	}
+
+	if !r.seen(3) || !r.seen(2) {
+		t.Fatal("Latest IDs not remembered")
+	}
+	if r.seen(1) {
+		t.Fatal("Oldest ID not forgotten")
+	}
+
+	// A size of 0 disables it.
+	DedupeSize = 0
+	r = recentIDs{}
+
+	if r.seen(1) || r.seen(1) {
+		t.Fatal("ID seen with deduplication disabled")
+	}
+}
+
+func TestCaptureExtras(t *testing.T) {
+	json.CaptureExtras = true
+	defer func() { json.CaptureExtras = false }()
+
+	var ev MessageCreateEvent
+
+	err := json.Unmarshal([]byte(`{
+		"id": "1",
+		"content": "hi",
+		"new_field": {"a": 1},
+		"author": {"id": "2", "username": "u", "new_user_field": true},
+		"mentions": [{"id": "3", "new_mention_field": 1}],
+		"member": {"nick": "n", "new_member_field": "x"}
+	}`), &ev)
+	if err != nil {
+		t.Fatal("Failed to unmarshal:", err)
+	}
+
+	if len(ev.Extras) != 1 || ev.Extras["new_field"].String() != `{"a": 1}` {
+		t.Fatal("Unexpected message extras:", ev.Extras)
+	}
+	if e := ev.Author.Extras; e == nil || len(*e) != 1 || (*e)["new_user_field"] == nil {
+		t.Fatal("Unexpected author extras:", e)
+	}
+	if e := ev.Mentions[0].Extras; e == nil || (*e)["new_mention_field"] == nil {
+		t.Fatal("Unexpected mention extras:", e)
+	}
+	// Users are still comparable.
+	if ev.Author == ev.Mentions[0].User {
+		t.Fatal("Unexpected equal users")
+	}
+	if _, ok := ev.Member.Extras["new_member_field"]; !ok || len(ev.Member.Extras) != 1 {
+		t.Fatal("Unexpected member extras:", ev.Member.Extras)
+	}
+}
+
+func TestInteractionSender(t *testing.T) {
+	var ev = InteractionCreateEvent{
+		Member: &discord.Member{User: discord.User{ID: 1}},
+	}
+	if u := ev.Sender(); u.ID != 1 {
+		t.Fatal("Unexpected guild sender:", u)
+	}
+
+	ev = InteractionCreateEvent{User: &discord.User{ID: 2}}
+	if u := ev.Sender(); u.ID != 2 {
+		t.Fatal("Unexpected DM sender:", u)
+	}
+}
@@ -55,28 +55,6 @@ func (i *IdentifyData) SetShard(id, num int) {
 	i.Shard[0], i.Shard[1] = id, num
 }
 
-// Intents is a new Discord API feature that's documented at
-// https://discordapp.com/developers/docs/topics/gateway#gateway-intents.
-type Intents uint32
-
-const (
-	IntentGuilds Intents = 1 << iota
-	IntentGuildMembers
-	IntentGuildBans
-	IntentGuildEmojis
-	IntentGuildIntegrations
-	IntentGuildWebhooks
-	IntentGuildInvites
-	IntentGuildVoiceStates
-	IntentGuildPresences
-	IntentGuildMessages
-	IntentGuildMessageReactions
-	IntentGuildMessageTyping
-	IntentDirectMessages
-	IntentDirectMessageReactions
-	IntentDirectMessageTyping
-)
-
 type Identifier struct {
 	IdentifyData
 
@@ -0,0 +1,79 @@
+package gateway
+
+import "github.com/diamondburned/arikawa/discord"
+
+// Intents is a new Discord API feature that's documented at
+// https://discordapp.com/developers/docs/topics/gateway#gateway-intents.
+type Intents uint32
+
+const (
+	IntentGuilds Intents = 1 << iota
+	IntentGuildMembers
+	IntentGuildBans
+	IntentGuildEmojis
+	IntentGuildIntegrations
+	IntentGuildWebhooks
+	IntentGuildInvites
+	IntentGuildVoiceStates
+	IntentGuildPresences
+	IntentGuildMessages
+	IntentGuildMessageReactions
+	IntentGuildMessageTyping
+	IntentDirectMessages
+	IntentDirectMessageReactions
+	IntentDirectMessageTyping
+	IntentMessageContent
+)
+
+// PrivilegedIntents are the intents that have to be enabled in the Developer
+// Portal.
+const PrivilegedIntents = IntentGuildMembers | IntentGuildPresences | IntentMessageContent
+
+func (i Intents) Has(intents Intents) bool {
+	return discord.HasFlag(uint64(i), uint64(intents))
+}
+
+func (i Intents) Add(intents Intents) Intents {
+	return i | intents
+}
+
+// EventIntents maps event names to the intents needed to receive them. If an
+// event has more than one intent, each of them receives the event from a
+// different source, such as guilds or direct messages. Events not in the map
+// are always received.
+var EventIntents = map[string]Intents{
+	"GUILD_CREATE":        IntentGuilds,
+	"GUILD_UPDATE":        IntentGuilds,
+	"GUILD_DELETE":        IntentGuilds,
+	"GUILD_ROLE_CREATE":   IntentGuilds,
+	"GUILD_ROLE_UPDATE":   IntentGuilds,
+	"GUILD_ROLE_DELETE":   IntentGuilds,
+	"CHANNEL_CREATE":      IntentGuilds,
+	"CHANNEL_UPDATE":      IntentGuilds,
+	"CHANNEL_DELETE":      IntentGuilds,
+	"CHANNEL_PINS_UPDATE": IntentGuilds | IntentDirectMessages,
+
+	"GUILD_MEMBER_ADD":    IntentGuildMembers,
+	"GUILD_MEMBER_UPDATE": IntentGuildMembers,
+	"GUILD_MEMBER_REMOVE": IntentGuildMembers,
+
+	"GUILD_BAN_ADD":    IntentGuildBans,
+	"GUILD_BAN_REMOVE": IntentGuildBans,
+
+	"GUILD_EMOJIS_UPDATE":       IntentGuildEmojis,
+	"GUILD_INTEGRATIONS_UPDATE": IntentGuildIntegrations,
+	"WEBHOOKS_UPDATE":           IntentGuildWebhooks,
+	"VOICE_STATE_UPDATE":        IntentGuildVoiceStates,
+	"PRESENCE_UPDATE":           IntentGuildPresences,
+
+	"MESSAGE_CREATE":      IntentGuildMessages | IntentDirectMessages,
+	"MESSAGE_UPDATE":      IntentGuildMessages | IntentDirectMessages,
+	"MESSAGE_DELETE":      IntentGuildMessages | IntentDirectMessages,
+	"MESSAGE_DELETE_BULK": IntentGuildMessages,
+
+	"MESSAGE_REACTION_ADD":        IntentGuildMessageReactions | IntentDirectMessageReactions,
+	"MESSAGE_REACTION_REMOVE":     IntentGuildMessageReactions | IntentDirectMessageReactions,
+	"MESSAGE_REACTION_REMOVE_ALL": IntentGuildMessageReactions | IntentDirectMessageReactions,
+
+	"TYPING_START": IntentGuildMessageTyping | IntentDirectMessageTyping,
+}
@@ -70,13 +70,13 @@ func (g *Gateway) HandleOP(op *wsutil.OP) error {
 			g.Sequence.Set(op.Sequence)
 		}
 
-		// Check if we know the event
+		// Check if we know the event. If not, send it as it is.
 		fn, ok := EventCreator[op.EventName]
 		if !ok {
-			return fmt.Errorf(
-				"Unknown event %s: %s",
-				op.EventName, string(op.Data),
-			)
+			wsutil.WSDebug("Unknown event", op.EventName)
+
+			g.Events <- &RawEvent{Type: op.EventName, Data: op.Data}
+			return nil
 		}
 
 		// Make a new pointer to the event
@@ -92,6 +92,14 @@ func (g *Gateway) HandleOP(op *wsutil.OP) error {
 			g.SessionID = ev.SessionID
 		}
 
+		// Drop messages that were already received.
+		if ev, ok := ev.(*MessageCreateEvent); ok && g.DedupeMessages {
+			if g.messages.seen(ev.ID) {
+				wsutil.WSDebug("Dropped duplicate message", ev.ID)
+				return nil
+			}
+		}
+
 		// Throw the event into a channel, it's valid now.
 		g.Events <- ev
 		return nil
@@ -1,5 +1,11 @@
 package gateway
 
+import (
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/utils/wsutil"
+	"github.com/pkg/errors"
+)
+
 type Shard [2]int
 
 func DefaultShard() *Shard {
@@ -14,3 +20,112 @@ func (s Shard) ShardID() int {
 func (s Shard) NumShards() int {
 	return s[1]
 }
+
+// ShardManager manages multiple Gateway shards of the same bot. All shards
+// send their events into the same Events channel, and they share the same
+// Identify rate limiters, so shards never identify faster than Discord allows,
+// including when they reconnect.
+type ShardManager struct {
+	Gateways []*Gateway
+
+	// Events is the channel that all shards send their events into.
+	Events chan Event
+}
+
+// NewShardManager creates a ShardManager with numShards shards. If numShards is
+// 0 or less, the number of shards recommended by Discord is used.
+func NewShardManager(token string, numShards int) (*ShardManager, error) {
+	URL, err := gatewayURL()
+	if err != nil {
+		return nil, err
+	}
+
+	if numShards <= 0 {
+		b, err := BotURL(token)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to get the recommended shards")
+		}
+
+		numShards = b.Shards
+		if numShards < 1 {
+			numShards = 1
+		}
+	}
+
+	return NewCustomShardManager(URL, token, numShards), nil
+}
+
+// NewCustomShardManager creates a ShardManager with numShards shards connecting
+// to the given Gateway URL.
+func NewCustomShardManager(gatewayURL, token string, numShards int) *ShardManager {
+	var m = &ShardManager{
+		Gateways: make([]*Gateway, numShards),
+		Events:   make(chan Event, wsutil.WSBuffer),
+	}
+
+	// The first shard's limiters are shared with all other shards.
+	var id *Identifier
+
+	for i := range m.Gateways {
+		g := NewCustomGateway(gatewayURL, token)
+		g.Events = m.Events
+		g.Identifier.SetShard(i, numShards)
+
+		if id == nil {
+			id = g.Identifier
+		} else {
+			g.Identifier.IdentifyShortLimit = id.IdentifyShortLimit
+			g.Identifier.IdentifyGlobalLimit = id.IdentifyGlobalLimit
+		}
+
+		m.Gateways[i] = g
+	}
+
+	return m
+}
+
+// Open opens all shards one by one. If a shard fails to open, the shards that
+// are already opened are closed.
+func (m *ShardManager) Open() error {
+	for i, g := range m.Gateways {
+		if err := g.Open(); err != nil {
+			for _, g := range m.Gateways[:i] {
+				g.Close()
+			}
+			return errors.Wrapf(err, "Failed to open shard %d", i)
+		}
+	}
+	return nil
+}
+
+// Close closes all shards. The first error is returned, if any.
+func (m *ShardManager) Close() (err error) {
+	for i, g := range m.Gateways {
+		if gerr := g.Close(); gerr != nil && err == nil {
+			err = errors.Wrapf(gerr, "Failed to close shard %d", i)
+		}
+	}
+	return
+}
+
+// UpdateStatus updates the presence on all shards. The first error is
+// returned, if any.
+func (m *ShardManager) UpdateStatus(data UpdateStatusData) (err error) {
+	for i, g := range m.Gateways {
+		if gerr := g.UpdateStatus(data); gerr != nil && err == nil {
+			err = errors.Wrapf(gerr, "Failed to update status on shard %d", i)
+		}
+	}
+	return
+}
+
+// FromGuildID returns the shard that receives the events of the given guild.
+// Commands for a guild, such as RequestGuildMembers, must be sent on this
+// shard.
+func (m *ShardManager) FromGuildID(guildID discord.Snowflake) *Gateway {
+	return m.Gateways[shardID(guildID, len(m.Gateways))]
+}
+
+func shardID(guildID discord.Snowflake, numShards int) int {
+	return int((uint64(guildID) >> 22) % uint64(numShards))
+}
@@ -21,6 +21,26 @@
 //         log.Println(m.Author.Username, "said", m.Content)
 //    })
 //
+// Channels
+//
+// AddHandler also accepts a channel of one of the events, or of interface{},
+// which then receives the matching events instead:
+//
+//    ch := make(chan *gateway.MessageCreateEvent, 64)
+//    defer s.AddHandler(ch)()
+//
+//    for m := range ch {
+//        log.Println(m.Author.Username, "said", m.Content)
+//    }
+//
+// Events are never dropped. Instead, a send to a full channel blocks where a
+// handler function would've been called: with the default asynchronous
+// Handler, that's a goroutine for each event, which pile up and may be
+// received out of order; with a Synchronous Handler, it holds up every other
+// handler and the Gateway. The buffer should therefore be large enough for
+// bursts of events. Removing the handler unblocks and drops all pending sends.
+// The channel is never closed, as other handlers may still send into it.
+//
 package handler
 
 import (
@@ -126,7 +146,8 @@ func (h *Handler) ChanFor(fn func(interface{}) bool) (out <-chan interface{}, ca
 }
 
 // AddHandler adds the handler, returning a function that would remove this
-// handler when called.
+// handler when called. The handler may be a function or a channel; refer to
+// the package documentation.
 func (h *Handler) AddHandler(handler interface{}) (rm func()) {
 	rm, err := h.addHandler(handler)
 	if err != nil {
@@ -172,13 +193,28 @@ func (h *Handler) addHandler(fn interface{}) (rm func(), err error) {
 		h.handlers = map[uint64]handler{}
 	}
 
+	// Channels need to know when to stop sending.
+	var closer chan struct{}
+	if r.isChan {
+		closer = make(chan struct{})
+		r.closer = closer
+	}
+
 	// Use the serial for the map:
 	h.handlers[serial] = *r
 
 	// Append the serial into the list of keys:
 	h.horders = append(h.horders, serial)
 
+	var once sync.Once
+
 	return func() {
+		// Drop the pending sends first, as a Synchronous Call holds the lock
+		// while sending.
+		if closer != nil {
+			once.Do(func() { close(closer) })
+		}
+
 		h.hmutex.Lock()
 		defer h.hmutex.Unlock()
 
@@ -199,12 +235,20 @@ type handler struct {
 	event    reflect.Type
 	callback reflect.Value
 	isIface  bool
+	isChan   bool
+
+	// closer is closed when a channel handler is removed.
+	closer chan struct{}
 }
 
 func reflectFn(function interface{}) (*handler, error) {
 	fnV := reflect.ValueOf(function)
 	fnT := fnV.Type()
 
+	if fnT.Kind() == reflect.Chan {
+		return reflectChan(fnV)
+	}
+
 	if fnT.Kind() != reflect.Func {
 		return nil, errors.New("given interface is not a function")
 	}
@@ -232,6 +276,28 @@ func reflectFn(function interface{}) (*handler, error) {
 	}, nil
 }
 
+func reflectChan(chV reflect.Value) (*handler, error) {
+	chT := chV.Type()
+
+	if chT.ChanDir()&reflect.SendDir == 0 {
+		return nil, errors.New("given channel is receive-only")
+	}
+
+	elemT := chT.Elem()
+	kind := elemT.Kind()
+
+	if kind != reflect.Ptr && kind != reflect.Interface {
+		return nil, errors.New("channel element is not pointer")
+	}
+
+	return &handler{
+		event:    elemT,
+		callback: chV,
+		isIface:  kind == reflect.Interface,
+		isChan:   true,
+	}, nil
+}
+
 func (h handler) not(event reflect.Type) bool {
 	if h.isIface {
 		return !event.Implements(h.event)
@@ -241,5 +307,13 @@ func (h handler) not(event reflect.Type) bool {
 }
 
 func (h handler) call(event reflect.Value) {
-	h.callback.Call([]reflect.Value{event})
+	if !h.isChan {
+		h.callback.Call([]reflect.Value{event})
+		return
+	}
+
+	reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: h.callback, Send: event},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(h.closer)},
+	})
 }
@@ -228,3 +228,57 @@ func BenchmarkReflect(b *testing.B) {
 		h.call(msgV)
 	}
 }
+
+func TestHandlerAddChan(t *testing.T) {
+	h := New()
+
+	var typed = make(chan *gateway.MessageCreateEvent, 1)
+	var any = make(chan interface{}, 2)
+
+	rmTyped := h.AddHandler(typed)
+	rmAny := h.AddHandler(any)
+	defer rmAny()
+
+	h.Call(newMessage("hime arikawa"))
+	h.Call(&gateway.TypingStartEvent{})
+
+	if m := <-typed; m.Content != "hime arikawa" {
+		t.Fatal("Unexpected message:", m.Content)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-any:
+		case <-time.After(time.Second):
+			t.Fatal("Missing event", i)
+		}
+	}
+
+	// Removing the handler should drop the pending send.
+	h.Synchronous = true
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		rmTyped()
+	}()
+
+	h.Call(newMessage("fills the buffer"))
+	h.Call(newMessage("blocks until removed"))
+
+	if m := <-typed; m.Content != "fills the buffer" {
+		t.Fatal("Unexpected message:", m.Content)
+	}
+
+	select {
+	case m := <-typed:
+		t.Fatal("Unexpected message after removal:", m.Content)
+	case <-time.After(5 * time.Millisecond):
+	}
+
+	// Invalid channel types.
+	if _, err := h.AddHandlerCheck(make(<-chan interface{})); err == nil {
+		t.Fatal("Expected error for a receive-only channel")
+	}
+	if _, err := h.AddHandlerCheck(make(chan gateway.MessageCreateEvent)); err == nil {
+		t.Fatal("Expected error for a non-pointer channel")
+	}
+}
@@ -0,0 +1,79 @@
+package session
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+	"github.com/pkg/errors"
+)
+
+var memberNonce uint64
+
+// RequestGuildMembers sends a Request Guild Members command for a single guild
+// and collects all GuildMembersChunkEvents that it's answered with. Each
+// request has its own nonce, so concurrent requests don't mix up their chunks.
+// If the context is done before all chunks arrive, the members collected so far
+// are returned along with the context's error, so a timeout should be given:
+//
+//    ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//    defer cancel()
+//
+//    members, err := s.RequestGuildMembers(ctx, gateway.RequestGuildMembersData{
+//        GuildID: []discord.Snowflake{guildID},
+//    })
+//
+func (s *Session) RequestGuildMembers(
+	ctx context.Context, data gateway.RequestGuildMembersData) ([]discord.Member, error) {
+
+	if len(data.GuildID) != 1 {
+		return nil, errors.New("Exactly one guild ID must be given")
+	}
+
+	if data.Nonce == "" {
+		data.Nonce = "arikawa-" + strconv.FormatUint(atomic.AddUint64(&memberNonce, 1), 10)
+	}
+
+	var g = s.Gateway
+	if s.Shards != nil {
+		g = s.Shards.FromGuildID(data.GuildID[0])
+	}
+
+	// Subscribe before sending, so no chunks are missed.
+	ch, cancel := s.ChanFor(func(v interface{}) bool {
+		c, ok := v.(*gateway.GuildMembersChunkEvent)
+		return ok && c.Nonce == data.Nonce
+	})
+	defer cancel()
+
+	if err := g.RequestGuildMembers(data); err != nil {
+		return nil, errors.Wrap(err, "Failed to request guild members")
+	}
+
+	var members []discord.Member
+	var received = map[int]bool{}
+
+	for {
+		select {
+		case v := <-ch:
+			c := v.(*gateway.GuildMembersChunkEvent)
+
+			// Ignore duplicate chunks.
+			if received[c.ChunkIndex] {
+				continue
+			}
+			received[c.ChunkIndex] = true
+
+			members = append(members, c.Members...)
+
+			if len(received) >= c.ChunkCount {
+				return members, nil
+			}
+
+		case <-ctx.Done():
+			return members, ctx.Err()
+		}
+	}
+}
@@ -30,6 +30,10 @@ type Session struct {
 	*api.Client
 	Gateway *gateway.Gateway
 
+	// Shards is non-nil if the Session is created with NewWithShards. Gateway is
+	// then the first shard.
+	Shards *gateway.ShardManager
+
 	// Command handler with inherited methods.
 	*handler.Handler
 
@@ -95,6 +99,14 @@ func NewWithGateway(gw *gateway.Gateway) *Session {
 	}
 }
 
+// NewWithShards creates a Session that receives events from all shards. The
+// events are handled the same way as with a single Gateway.
+func NewWithShards(sm *gateway.ShardManager) *Session {
+	s := NewWithGateway(sm.Gateways[0])
+	s.Shards = sm
+	return s
+}
+
 func (s *Session) Open() error {
 	// Start the handler beforehand so no events are missed.
 	stop := make(chan struct{})
@@ -102,12 +114,26 @@ func (s *Session) Open() error {
 	go s.startHandler(stop)
 
 	// Set the AfterClose's handler.
-	s.Gateway.AfterClose = func(err error) {
+	var afterClose = func(err error) {
 		s.Handler.Call(&Closed{
 			Error: err,
 		})
 	}
 
+	if s.Shards != nil {
+		for _, g := range s.Shards.Gateways {
+			g.AfterClose = afterClose
+		}
+
+		if err := s.Shards.Open(); err != nil {
+			return errors.Wrap(err, "Failed to start shards")
+		}
+
+		return nil
+	}
+
+	s.Gateway.AfterClose = afterClose
+
 	if err := s.Gateway.Open(); err != nil {
 		return errors.Wrap(err, "Failed to start gateway")
 	}
@@ -115,6 +141,15 @@ func (s *Session) Open() error {
 	return nil
 }
 
+// UpdateStatus updates the presence of the current user on the Gateway, or on
+// all shards if the Session has them. Refer to Gateway's UpdateStatus.
+func (s *Session) UpdateStatus(data gateway.UpdateStatusData) error {
+	if s.Shards != nil {
+		return s.Shards.UpdateStatus(data)
+	}
+	return s.Gateway.UpdateStatus(data)
+}
+
 func (s *Session) startHandler(stop <-chan struct{}) {
 	for {
 		select {
@@ -131,6 +166,9 @@ func (s *Session) Close() error {
 	s.close()
 
 	// Close the websocket
+	if s.Shards != nil {
+		return s.Shards.Close()
+	}
 	return s.Gateway.Close()
 }
 
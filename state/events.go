@@ -0,0 +1,47 @@
+package state
+
+import (
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/gateway"
+)
+
+// The events below are called in the State's Handler after their gateway
+// counterparts, once the State is updated. Old is the value the State had
+// before the update, or nil if it had none. The updated value can be fetched
+// from the State as usual.
+//
+//    s.AddHandler(func(ev *state.GuildMemberUpdateEvent) {
+//        if ev.Old != nil && ev.Old.Nick != ev.Nick {
+//            log.Println("Nickname changed from", ev.Old.Nick, "to", ev.Nick)
+//        }
+//    })
+
+// GuildUpdateEvent is called after a gateway.GuildUpdateEvent.
+type GuildUpdateEvent struct {
+	*gateway.GuildUpdateEvent
+	Old *discord.Guild
+}
+
+// GuildMemberUpdateEvent is called after a gateway.GuildMemberUpdateEvent.
+type GuildMemberUpdateEvent struct {
+	*gateway.GuildMemberUpdateEvent
+	Old *discord.Member
+}
+
+// GuildRoleUpdateEvent is called after a gateway.GuildRoleUpdateEvent.
+type GuildRoleUpdateEvent struct {
+	*gateway.GuildRoleUpdateEvent
+	Old *discord.Role
+}
+
+// ChannelUpdateEvent is called after a gateway.ChannelUpdateEvent.
+type ChannelUpdateEvent struct {
+	*gateway.ChannelUpdateEvent
+	Old *discord.Channel
+}
+
+// MessageUpdateEvent is called after a gateway.MessageUpdateEvent.
+type MessageUpdateEvent struct {
+	*gateway.MessageUpdateEvent
+	Old *discord.Message
+}
@@ -4,6 +4,7 @@ package state
 
 import (
 	"context"
+	"strings"
 	"sync"
 
 	"github.com/diamondburned/arikawa/discord"
@@ -32,6 +33,12 @@ type State struct {
 	// important.
 	StateLog func(error)
 
+	// SearchCachedMembers makes SearchGuildMembers search the members in the
+	// Store instead of asking Discord. Only enable this if the Store has the
+	// full member lists, e.g. with the GuildMembers intent and
+	// RequestGuildMembers, as members that aren't cached won't be found.
+	SearchCachedMembers bool
+
 	// PreHandler is the manual hook that is executed before the State handler
 	// is. This should only be used for low-level operations.
 	// It's recommended to set Synchronous to true if you mutate the events.
@@ -48,6 +55,9 @@ type State struct {
 	// again.
 	fewMessages map[discord.Snowflake]struct{}
 	fewMutex    *sync.Mutex
+
+	// memberCounts is nil if the State isn't made by NewFromSession.
+	memberCounts *memberCounts
 }
 
 func New(token string) (*State, error) {
@@ -71,6 +81,8 @@ func NewFromSession(s *session.Session, store Store) (*State, error) {
 		StateLog:    func(err error) {},
 		fewMessages: map[discord.Snowflake]struct{}{},
 		fewMutex:    new(sync.Mutex),
+
+		memberCounts: newMemberCounts(),
 	}
 
 	return state, state.hookSession()
@@ -153,6 +165,9 @@ func (s *State) MemberColor(guildID, userID discord.Snowflake) discord.Color {
 
 ////
 
+// Permissions calculates the member's permissions in the guild channel, with
+// the guild's roles and the channel's overwrites. The guild is the channel's
+// guild. See discord.CalcOverwrites.
 func (s *State) Permissions(channelID, userID discord.Snowflake) (discord.Permissions, error) {
 	ch, err := s.Channel(channelID)
 	if err != nil {
@@ -172,6 +187,22 @@ func (s *State) Permissions(channelID, userID discord.Snowflake) (discord.Permis
 	return discord.CalcOverwrites(*g, *ch, *m), nil
 }
 
+// GuildPermissions calculates the member's permissions in the guild from their
+// roles only. See discord.CalcPermissions.
+func (s *State) GuildPermissions(guildID, userID discord.Snowflake) (discord.Permissions, error) {
+	g, err := s.Guild(guildID)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to get guild")
+	}
+
+	m, err := s.Member(guildID, userID)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to get member")
+	}
+
+	return discord.CalcPermissions(*g, *m), nil
+}
+
 ////
 
 func (s *State) Me() (*discord.User, error) {
@@ -384,6 +415,57 @@ func (s *State) Members(guildID discord.Snowflake) ([]discord.Member, error) {
 	})
 }
 
+// SearchGuildMembers returns up to limit members whose username or nickname
+// starts with the query, case-insensitively, like Discord does. The Store is
+// searched if SearchCachedMembers is true and it has the guild's members,
+// otherwise Discord is asked. Members found through the API are cached.
+func (s *State) SearchGuildMembers(
+	guildID discord.Snowflake, query string, limit uint) ([]discord.Member, error) {
+
+	if s.SearchCachedMembers {
+		if ms, err := s.Store.Members(guildID); err == nil {
+			return searchMembers(ms, query, limit), nil
+		}
+	}
+
+	ms, err := s.Session.SearchGuildMembers(guildID, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range ms {
+		if err := s.Store.MemberSet(guildID, &ms[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return ms, nil
+}
+
+// searchMembers filters the members like the member search endpoint does.
+func searchMembers(ms []discord.Member, query string, limit uint) []discord.Member {
+	if limit == 0 {
+		limit = 1
+	}
+
+	query = strings.ToLower(query)
+
+	var found []discord.Member
+	for _, m := range ms {
+		if uint(len(found)) >= limit {
+			break
+		}
+
+		if strings.HasPrefix(strings.ToLower(m.User.Username), query) ||
+			strings.HasPrefix(strings.ToLower(m.Nick), query) {
+
+			found = append(found, m)
+		}
+	}
+
+	return found
+}
+
 ////
 
 func (s *State) Message(
@@ -433,6 +515,11 @@ func (s *State) Messages(channelID discord.Snowflake) ([]discord.Message, error)
 		s.fewMutex.Unlock()
 	}
 
+	// Messages would fetch everything with a 0 limit.
+	if maxMsgs <= 0 {
+		return nil, nil
+	}
+
 	ms, err = s.Session.Messages(channelID, uint(maxMsgs))
 	if err != nil {
 		return nil, err
@@ -475,7 +562,8 @@ func (s *State) Messages(channelID discord.Snowflake) ([]discord.Message, error)
 ////
 
 // Presence checks the state for user presences. If no guildID is given, it will
-// look for the presence in all guilds.
+// look for the presence in all guilds. Offline users have no presence, so
+// ErrStoreNotFound is returned for them.
 func (s *State) Presence(guildID, userID discord.Snowflake) (*discord.Presence, error) {
 	p, err := s.Store.Presence(guildID, userID)
 	if err == nil {
@@ -501,6 +589,34 @@ func (s *State) Presence(guildID, userID discord.Snowflake) (*discord.Presence,
 
 ////
 
+// ChannelVoiceStates returns the voice states of all users connected to the
+// voice channel.
+func (s *State) ChannelVoiceStates(channelID discord.Snowflake) ([]discord.VoiceState, error) {
+	c, err := s.Channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := s.Store.VoiceStates(c.GuildID)
+	if err != nil {
+		if err == ErrStoreNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var filtered = states[:0]
+	for _, vs := range states {
+		if vs.ChannelID == channelID {
+			filtered = append(filtered, vs)
+		}
+	}
+
+	return filtered, nil
+}
+
+////
+
 func (s *State) Role(guildID, roleID discord.Snowflake) (*discord.Role, error) {
 
 	r, err := s.Store.Role(guildID, roleID)
@@ -0,0 +1,98 @@
+package state
+
+import (
+	"sync"
+
+	"github.com/diamondburned/arikawa/discord"
+)
+
+// memberCounts keeps the member count of each guild. Counts are set on Guild
+// Create and kept up to date with member events.
+type memberCounts struct {
+	mutex  sync.Mutex
+	counts map[discord.Snowflake]uint64
+}
+
+func newMemberCounts() *memberCounts {
+	return &memberCounts{counts: map[discord.Snowflake]uint64{}}
+}
+
+func (m *memberCounts) set(guildID discord.Snowflake, count uint64) {
+	m.mutex.Lock()
+	m.counts[guildID] = count
+	m.mutex.Unlock()
+}
+
+func (m *memberCounts) remove(guildID discord.Snowflake) {
+	m.mutex.Lock()
+	delete(m.counts, guildID)
+	m.mutex.Unlock()
+}
+
+// add adds delta to the guild's count, if the guild's count is known.
+func (m *memberCounts) add(guildID discord.Snowflake, delta int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	count, ok := m.counts[guildID]
+	if !ok || (delta < 0 && count == 0) {
+		return
+	}
+
+	m.counts[guildID] = uint64(int64(count) + int64(delta))
+}
+
+func (m *memberCounts) total() (total uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, count := range m.counts {
+		total += count
+	}
+	return
+}
+
+// GuildCount returns the number of guilds in the Store, which is kept up to
+// date with Guild Create and Guild Delete events. Guilds that are unavailable
+// aren't counted. 0 is returned if the Store can't list its guilds.
+func (s *State) GuildCount() int {
+	guilds, err := s.Store.Guilds()
+	if err != nil {
+		return 0
+	}
+	return len(guilds)
+}
+
+// ShardGuildCounts returns the number of guilds in the Store for each shard,
+// indexed by the shard ID. It returns nil if the Session isn't sharded; refer
+// to GuildCount instead.
+func (s *State) ShardGuildCounts() []int {
+	if s.Session == nil || s.Shards == nil {
+		return nil
+	}
+
+	var counts = make([]int, len(s.Shards.Gateways))
+
+	guilds, err := s.Store.Guilds()
+	if err != nil {
+		return counts
+	}
+
+	for _, guild := range guilds {
+		counts[s.Shards.FromGuildID(guild.ID).Identifier.Shard.ShardID()]++
+	}
+
+	return counts
+}
+
+// MemberCount returns an estimate of the number of members across all guilds,
+// from the member counts given by Discord when the guilds are created, updated
+// with members joining and leaving. Members in several guilds are counted
+// once per guild. It's always 0 for States not made with a constructor such as
+// New.
+func (s *State) MemberCount() uint64 {
+	if s.memberCounts == nil {
+		return 0
+	}
+	return s.memberCounts.total()
+}
@@ -11,14 +11,21 @@ func (s *State) hookSession() error {
 		if s.PreHandler != nil {
 			s.PreHandler.Call(iface)
 		}
-		s.onEvent(iface)
+		ev := s.onEvent(iface)
 		s.Handler.Call(iface)
+
+		// Call the event with the old value after the original one.
+		if ev != nil {
+			s.Handler.Call(ev)
+		}
 	})
 
 	return nil
 }
 
-func (s *State) onEvent(iface interface{}) {
+// onEvent updates the state with the event. If the event updated an existing
+// value, an event with the old value is returned.
+func (s *State) onEvent(iface interface{}) interface{} {
 	switch ev := iface.(type) {
 	case *gateway.ReadyEvent:
 		// Set Ready to the state
@@ -34,8 +41,12 @@ func (s *State) onEvent(iface interface{}) {
 		}
 
 		// Handle guilds
-		for i := range ev.Guilds {
+		for i, guild := range ev.Guilds {
 			s.batchLog(handleGuildCreate(s.Store, &ev.Guilds[i])...)
+
+			if s.memberCounts != nil && !guild.Unavailable {
+				s.memberCounts.set(guild.ID, guild.MemberCount)
+			}
 		}
 
 		// Handle private channels
@@ -53,26 +64,52 @@ func (s *State) onEvent(iface interface{}) {
 	case *gateway.GuildCreateEvent:
 		s.batchLog(handleGuildCreate(s.Store, ev)...)
 
+		if s.memberCounts != nil && !ev.Unavailable {
+			s.memberCounts.set(ev.ID, ev.MemberCount)
+		}
+
 	case *gateway.GuildUpdateEvent:
+		var old *discord.Guild
+		if g, err := s.Store.Guild(ev.ID); err == nil {
+			cpy := *g
+			old = &cpy
+		}
+
 		if err := s.Store.GuildSet((*discord.Guild)(ev)); err != nil {
 			s.stateErr(err, "Failed to update guild in state")
+			return nil
 		}
 
+		return &GuildUpdateEvent{ev, old}
+
 	case *gateway.GuildDeleteEvent:
 		if err := s.Store.GuildRemove(ev.ID); err != nil {
 			s.stateErr(err, "Failed to delete guild in state")
 		}
 
+		if s.memberCounts != nil {
+			s.memberCounts.remove(ev.ID)
+		}
+
 	case *gateway.GuildMemberAddEvent:
 		if err := s.Store.MemberSet(ev.GuildID, &ev.Member); err != nil {
 			s.stateErr(err, "Failed to add a member in state")
 		}
 
+		if s.memberCounts != nil {
+			s.memberCounts.add(ev.GuildID, 1)
+		}
+
 	case *gateway.GuildMemberUpdateEvent:
+		var old *discord.Member
+
 		m, err := s.Store.Member(ev.GuildID, ev.User.ID)
 		if err != nil {
 			// We can't do much here.
 			m = &discord.Member{}
+		} else {
+			cpy := *m
+			old = &cpy
 		}
 
 		// Update available fields from ev into m
@@ -80,13 +117,29 @@ func (s *State) onEvent(iface interface{}) {
 
 		if err := s.Store.MemberSet(ev.GuildID, m); err != nil {
 			s.stateErr(err, "Failed to update a member in state")
+			return nil
 		}
 
+		return &GuildMemberUpdateEvent{ev, old}
+
 	case *gateway.GuildMemberRemoveEvent:
 		if err := s.Store.MemberRemove(ev.GuildID, ev.User.ID); err != nil {
 			s.stateErr(err, "Failed to remove a member in state")
 		}
 
+		if s.memberCounts != nil {
+			s.memberCounts.add(ev.GuildID, -1)
+		}
+
+		// The member's presence and voice state are gone with them. These are
+		// likely not in the state anyway.
+		if err := s.Store.PresenceRemove(ev.GuildID, ev.User.ID); err != nil && err != ErrStoreNotFound {
+			s.stateErr(err, "Failed to remove a member's presence in state")
+		}
+		if err := s.Store.VoiceStateRemove(ev.GuildID, ev.User.ID); err != nil && err != ErrStoreNotFound {
+			s.stateErr(err, "Failed to remove a member's voice state in state")
+		}
+
 	case *gateway.GuildMembersChunkEvent:
 		for _, m := range ev.Members {
 			m := m
@@ -110,10 +163,19 @@ func (s *State) onEvent(iface interface{}) {
 		}
 
 	case *gateway.GuildRoleUpdateEvent:
+		var old *discord.Role
+		if r, err := s.Store.Role(ev.GuildID, ev.Role.ID); err == nil {
+			cpy := *r
+			old = &cpy
+		}
+
 		if err := s.Store.RoleSet(ev.GuildID, &ev.Role); err != nil {
 			s.stateErr(err, "Failed to update a role in state")
+			return nil
 		}
 
+		return &GuildRoleUpdateEvent{ev, old}
+
 	case *gateway.GuildRoleDeleteEvent:
 		if err := s.Store.RoleRemove(ev.GuildID, ev.RoleID); err != nil {
 			s.stateErr(err, "Failed to remove a role in state")
@@ -130,10 +192,19 @@ func (s *State) onEvent(iface interface{}) {
 		}
 
 	case *gateway.ChannelUpdateEvent:
+		var old *discord.Channel
+		if ch, err := s.Store.Channel(ev.ID); err == nil {
+			cpy := *ch
+			old = &cpy
+		}
+
 		if err := s.Store.ChannelSet((*discord.Channel)(ev)); err != nil {
 			s.stateErr(err, "Failed to update a channel in state")
+			return nil
 		}
 
+		return &ChannelUpdateEvent{ev, old}
+
 	case *gateway.ChannelDeleteEvent:
 		if err := s.Store.ChannelRemove((*discord.Channel)(ev)); err != nil {
 			s.stateErr(err, "Failed to remove a channel in state")
@@ -148,10 +219,19 @@ func (s *State) onEvent(iface interface{}) {
 		}
 
 	case *gateway.MessageUpdateEvent:
+		var old *discord.Message
+		if m, err := s.Store.Message(ev.ChannelID, ev.ID); err == nil {
+			cpy := *m
+			old = &cpy
+		}
+
 		if err := s.Store.MessageSet(&ev.Message); err != nil {
 			s.stateErr(err, "Failed to update a message in state")
+			return nil
 		}
 
+		return &MessageUpdateEvent{ev, old}
+
 	case *gateway.MessageDeleteEvent:
 		if err := s.Store.MessageRemove(ev.ChannelID, ev.ID); err != nil {
 			s.stateErr(err, "Failed to delete a message in state")
@@ -225,8 +305,17 @@ func (s *State) onEvent(iface interface{}) {
 
 	case *gateway.PresenceUpdateEvent:
 		presence := (*discord.Presence)(ev)
-		if err := s.Store.PresenceSet(ev.GuildID, presence); err != nil {
-			s.stateErr(err, "Failed to update presence in state")
+
+		// Offline users aren't kept, the same way they're not in Ready or
+		// Guild Create.
+		if presence.Status == discord.OfflineStatus {
+			if err := s.Store.PresenceRemove(ev.GuildID, ev.User.ID); err != nil && err != ErrStoreNotFound {
+				s.stateErr(err, "Failed to remove presence from state")
+			}
+		} else {
+			if err := s.Store.PresenceSet(ev.GuildID, presence); err != nil {
+				s.stateErr(err, "Failed to update presence in state")
+			}
 		}
 
 	case *gateway.PresencesReplaceEvent:
@@ -268,6 +357,8 @@ func (s *State) onEvent(iface interface{}) {
 			}
 		}
 	}
+
+	return nil
 }
 
 func (s *State) stateErr(err error, wrap string) {
@@ -28,7 +28,14 @@ type DefaultStore struct {
 }
 
 type DefaultStoreOptions struct {
+	// MaxMessages is the maximum number of messages kept per channel. The
+	// oldest messages are evicted first. 0 disables caching messages.
 	MaxMessages uint // default 50
+
+	// MaxTotalMessages is the maximum number of messages kept across all
+	// channels. Once it's reached, the oldest message of all channels is
+	// evicted. 0 means no limit.
+	MaxTotalMessages uint
 }
 
 var _ Store = (*DefaultStore)(nil)
@@ -397,7 +404,7 @@ func (s *DefaultStore) MemberRemove(guildID, userID discord.Snowflake) error {
 	// Try and see if this member is already in the slice
 	for i, m := range ms {
 		if m.User.ID == userID {
-			ms = append(ms, ms[i+1:]...)
+			ms = append(ms[:i], ms[i+1:]...)
 			s.members[guildID] = ms
 
 			return nil
@@ -447,62 +454,82 @@ func (s *DefaultStore) MessageSet(message *discord.Message) error {
 	s.mut.Lock()
 	defer s.mut.Unlock()
 
+	var max = s.MaxMessages()
+	if max <= 0 {
+		return nil
+	}
+
 	ms, ok := s.messages[message.ChannelID]
 	if !ok {
-		ms = make([]discord.Message, 0, s.MaxMessages()+1)
+		ms = make([]discord.Message, 0, max+1)
 	}
 
 	// Check if we already have the message.
 	for i, m := range ms {
 		if m.ID == message.ID {
-			// Thanks, Discord.
-			if message.Content != "" {
-				m.Content = message.Content
-			}
-			if message.EditedTimestamp.Valid() {
-				m.EditedTimestamp = message.EditedTimestamp
-			}
-			if message.Mentions != nil {
-				m.Mentions = message.Mentions
-			}
-			if message.Embeds != nil {
-				m.Embeds = message.Embeds
-			}
-			if message.Attachments != nil {
-				m.Attachments = message.Attachments
-			}
-			if message.Timestamp.Valid() {
-				m.Timestamp = message.Timestamp
-			}
-			if message.Author.ID.Valid() {
-				m.Author = message.Author
-			}
-			if message.Reactions != nil {
-				m.Reactions = message.Reactions
-			}
-
+			mergeMessage(&m, message)
 			ms[i] = m
 			return nil
 		}
 	}
 
-	// Prepend the latest message at the end
+	// Messages are kept from the newest to the oldest, the same order the API
+	// returns them in. Since IDs are chronological, search for where the
+	// message belongs.
+	i := sort.Search(len(ms), func(i int) bool {
+		return ms[i].ID < message.ID
+	})
+
+	// The message is older than all messages, and there's no room for it.
+	if i >= max {
+		return nil
+	}
 
-	if end := s.MaxMessages(); len(ms) >= end {
-		// Copy hack to prepend. This copies the 0th-(end-1)th entries to
-		// 1st-endth.
-		copy(ms[1:end], ms[0:end-1])
-		// Then, set the 0th entry.
-		ms[0] = *message
+	ms = append(ms, discord.Message{})
+	copy(ms[i+1:], ms[i:])
+	ms[i] = *message
 
-	} else {
-		ms = append(ms, *message)
+	// Evict the oldest message if the channel is over the limit.
+	if len(ms) > max {
+		ms = ms[:max]
 	}
 
 	s.messages[message.ChannelID] = ms
+
+	if s.MaxTotalMessages > 0 {
+		s.evictMessages(int(s.MaxTotalMessages))
+	}
+
 	return nil
 }
 
+// evictMessages evicts the oldest messages of all channels until there are
+// at most max messages. The mutex must be acquired.
+func (s *DefaultStore) evictMessages(max int) {
+	var total int
+	for _, ms := range s.messages {
+		total += len(ms)
+	}
+
+	for ; total > max; total-- {
+		var oldestID discord.Snowflake
+		var oldest discord.Snowflake // channelID
+
+		for channelID, ms := range s.messages {
+			if len(ms) == 0 {
+				continue
+			}
+			if id := ms[len(ms)-1].ID; oldestID == 0 || id < oldestID {
+				oldestID = id
+				oldest = channelID
+			}
+		}
+
+		ms := s.messages[oldest]
+		s.messages[oldest] = ms[:len(ms)-1]
+	}
+}
+
 func (s *DefaultStore) MessageRemove(channelID, messageID discord.Snowflake) error {
 	s.mut.Lock()
 	defer s.mut.Unlock()
@@ -739,3 +766,33 @@ func (s *DefaultStore) VoiceStateRemove(guildID, userID discord.Snowflake) error
 
 	return ErrStoreNotFound
 }
+
+// mergeMessage merges the fields of src, which may be a partial message from
+// a Message Update event, into dst.
+func mergeMessage(dst, src *discord.Message) {
+	// Thanks, Discord.
+	if src.Content != "" {
+		dst.Content = src.Content
+	}
+	if src.EditedTimestamp.Valid() {
+		dst.EditedTimestamp = src.EditedTimestamp
+	}
+	if src.Mentions != nil {
+		dst.Mentions = src.Mentions
+	}
+	if src.Embeds != nil {
+		dst.Embeds = src.Embeds
+	}
+	if src.Attachments != nil {
+		dst.Attachments = src.Attachments
+	}
+	if src.Timestamp.Valid() {
+		dst.Timestamp = src.Timestamp
+	}
+	if src.Author.ID.Valid() {
+		dst.Author = src.Author
+	}
+	if src.Reactions != nil {
+		dst.Reactions = src.Reactions
+	}
+}
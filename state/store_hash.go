@@ -0,0 +1,584 @@
+package state
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/discord"
+	"github.com/diamondburned/arikawa/utils/json"
+	"github.com/pkg/errors"
+)
+
+// HashBackend is the storage used by HashStore. Its methods map one-to-one to
+// Redis's hash commands, so it could be implemented with any Redis client:
+//
+//    type RedisBackend struct{ *redis.Client }
+//
+//    func (r RedisBackend) HGet(key, field string) ([]byte, error) {
+//        b, err := r.Client.HGet(key, field).Bytes()
+//        if err == redis.Nil {
+//            return nil, state.ErrStoreNotFound
+//        }
+//        return b, err
+//    }
+//    ...
+//
+// A full example is in _example/redis.
+//
+// All methods must be safe to be called concurrently.
+type HashBackend interface {
+	// HGet returns ErrStoreNotFound if the field isn't in the hash.
+	HGet(key, field string) ([]byte, error)
+	// HGetAll returns an empty map if the hash doesn't exist.
+	HGetAll(key string) (map[string][]byte, error)
+	HSet(key, field string, value []byte) error
+	HDel(key string, fields ...string) error
+	Expire(key string, ttl time.Duration) error
+}
+
+type HashStoreOptions struct {
+	// Prefix is prepended to all keys, so several bots could share a backend.
+	Prefix string
+	// TTL is the expiry set on a hash every time it's written to. 0 means
+	// hashes never expire.
+	//
+	// The TTL applies to whole hashes, and some hashes are shared: all guilds
+	// are in one hash, and so are all channel IDs. A write to any guild keeps
+	// every guild alive, and they all expire together once no guild has been
+	// written to for the TTL.
+	TTL time.Duration
+	// MaxMessages is the maximum number of messages kept per channel.
+	MaxMessages uint // default 50
+}
+
+// HashStore is a Store that keeps JSON-encoded objects in hashes, which allows
+// the state to be shared between several processes, such as one per shard, and
+// to survive restarts.
+//
+// Writes are serialized within a process, but partial updates, such as
+// RoleSet or merging a message update, read the object before writing it back.
+// If two processes update the same object at once, the last write wins.
+type HashStore struct {
+	*HashStoreOptions
+	Backend HashBackend
+
+	mut sync.Mutex
+}
+
+var _ Store = (*HashStore)(nil)
+
+func NewHashStore(backend HashBackend, opts *HashStoreOptions) *HashStore {
+	if opts == nil {
+		opts = &HashStoreOptions{
+			MaxMessages: 50,
+		}
+	}
+
+	return &HashStore{
+		HashStoreOptions: opts,
+		Backend:          backend,
+	}
+}
+
+func (s *HashStore) key(name string) string {
+	return s.Prefix + name
+}
+
+// idKey returns the key of a hash that belongs to a guild or channel.
+func (s *HashStore) idKey(name string, id discord.Snowflake) string {
+	return s.Prefix + name + ":" + id.String()
+}
+
+func (s *HashStore) get(key string, field discord.Snowflake, v interface{}) error {
+	b, err := s.Backend.HGet(key, field.String())
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(json.Unmarshal(b, v), "Failed to decode "+key)
+}
+
+// getAll calls fn with each value in the hash. ErrStoreNotFound is returned if
+// the hash is empty.
+func (s *HashStore) getAll(key string, fn func(b []byte) error) error {
+	all, err := s.Backend.HGetAll(key)
+	if err != nil {
+		return err
+	}
+	if len(all) == 0 {
+		return ErrStoreNotFound
+	}
+
+	for _, b := range all {
+		if err := fn(b); err != nil {
+			return errors.Wrap(err, "Failed to decode "+key)
+		}
+	}
+
+	return nil
+}
+
+func (s *HashStore) set(key string, field discord.Snowflake, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode "+key)
+	}
+
+	if err := s.Backend.HSet(key, field.String(), b); err != nil {
+		return err
+	}
+
+	if s.TTL > 0 {
+		return s.Backend.Expire(key, s.TTL)
+	}
+
+	return nil
+}
+
+func (s *HashStore) del(key string, field discord.Snowflake) error {
+	return s.Backend.HDel(key, field.String())
+}
+
+////
+
+func (s *HashStore) Me() (*discord.User, error) {
+	var u discord.User
+	if err := s.get(s.key("self"), 0, &u); err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (s *HashStore) MyselfSet(me *discord.User) error {
+	return s.set(s.key("self"), 0, me)
+}
+
+////
+
+// Channel looks up the guild of the channel first, since channels are stored
+// per guild.
+func (s *HashStore) Channel(id discord.Snowflake) (*discord.Channel, error) {
+	var guildID discord.Snowflake
+	if err := s.get(s.key("channelguilds"), id, &guildID); err != nil {
+		return nil, err
+	}
+
+	var ch discord.Channel
+	if err := s.get(s.idKey("channels", guildID), id, &ch); err != nil {
+		return nil, err
+	}
+
+	return &ch, nil
+}
+
+func (s *HashStore) Channels(guildID discord.Snowflake) ([]discord.Channel, error) {
+	var chs []discord.Channel
+
+	return chs, s.getAll(s.idKey("channels", guildID), func(b []byte) error {
+		var ch discord.Channel
+		if err := json.Unmarshal(b, &ch); err != nil {
+			return err
+		}
+
+		chs = append(chs, ch)
+		return nil
+	})
+}
+
+func (s *HashStore) CreatePrivateChannel(recipient discord.Snowflake) (*discord.Channel, error) {
+	chs, err := s.PrivateChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ch := range chs {
+		if ch.Type == discord.DirectMessage && len(ch.DMRecipients) > 0 &&
+			ch.DMRecipients[0].ID == recipient {
+
+			return &ch, nil
+		}
+	}
+
+	return nil, ErrStoreNotFound
+}
+
+// PrivateChannels returns a list of Direct Message channels randomly ordered.
+func (s *HashStore) PrivateChannels() ([]discord.Channel, error) {
+	return s.Channels(0)
+}
+
+func (s *HashStore) ChannelSet(channel *discord.Channel) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	var key = s.idKey("channels", channel.GuildID)
+
+	if channel.Permissions == nil {
+		var old discord.Channel
+		if err := s.get(key, channel.ID, &old); err == nil {
+			channel.Permissions = old.Permissions
+		}
+	}
+
+	if err := s.set(key, channel.ID, channel); err != nil {
+		return err
+	}
+
+	return s.set(s.key("channelguilds"), channel.ID, &channel.GuildID)
+}
+
+func (s *HashStore) ChannelRemove(channel *discord.Channel) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if err := s.del(s.idKey("channels", channel.GuildID), channel.ID); err != nil {
+		return err
+	}
+
+	return s.del(s.key("channelguilds"), channel.ID)
+}
+
+////
+
+func (s *HashStore) Emoji(guildID, emojiID discord.Snowflake) (*discord.Emoji, error) {
+	es, err := s.Emojis(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range es {
+		if e.ID == emojiID {
+			return &e, nil
+		}
+	}
+
+	return nil, ErrStoreNotFound
+}
+
+func (s *HashStore) Emojis(guildID discord.Snowflake) ([]discord.Emoji, error) {
+	g, err := s.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.Emojis, nil
+}
+
+func (s *HashStore) EmojiSet(guildID discord.Snowflake, emojis []discord.Emoji) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	g, err := s.Guild(guildID)
+	if err != nil {
+		return err
+	}
+
+	g.Emojis = emojis
+	return s.set(s.key("guilds"), guildID, g)
+}
+
+////
+
+func (s *HashStore) Guild(id discord.Snowflake) (*discord.Guild, error) {
+	var g discord.Guild
+	if err := s.get(s.key("guilds"), id, &g); err != nil {
+		return nil, err
+	}
+
+	return &g, nil
+}
+
+func (s *HashStore) Guilds() ([]discord.Guild, error) {
+	var gs []discord.Guild
+
+	err := s.getAll(s.key("guilds"), func(b []byte) error {
+		var g discord.Guild
+		if err := json.Unmarshal(b, &g); err != nil {
+			return err
+		}
+
+		gs = append(gs, g)
+		return nil
+	})
+
+	sort.Slice(gs, func(i, j int) bool {
+		return gs[i].ID > gs[j].ID
+	})
+
+	return gs, err
+}
+
+func (s *HashStore) GuildSet(guild *discord.Guild) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if g, err := s.Guild(guild.ID); err == nil {
+		// preserve state stuff
+		if guild.Roles == nil {
+			guild.Roles = g.Roles
+		}
+		if guild.Emojis == nil {
+			guild.Emojis = g.Emojis
+		}
+	}
+
+	return s.set(s.key("guilds"), guild.ID, guild)
+}
+
+func (s *HashStore) GuildRemove(id discord.Snowflake) error {
+	return s.del(s.key("guilds"), id)
+}
+
+////
+
+func (s *HashStore) Member(guildID, userID discord.Snowflake) (*discord.Member, error) {
+	var m discord.Member
+	if err := s.get(s.idKey("members", guildID), userID, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (s *HashStore) Members(guildID discord.Snowflake) ([]discord.Member, error) {
+	var ms []discord.Member
+
+	return ms, s.getAll(s.idKey("members", guildID), func(b []byte) error {
+		var m discord.Member
+		if err := json.Unmarshal(b, &m); err != nil {
+			return err
+		}
+
+		ms = append(ms, m)
+		return nil
+	})
+}
+
+func (s *HashStore) MemberSet(guildID discord.Snowflake, member *discord.Member) error {
+	return s.set(s.idKey("members", guildID), member.User.ID, member)
+}
+
+func (s *HashStore) MemberRemove(guildID, userID discord.Snowflake) error {
+	return s.del(s.idKey("members", guildID), userID)
+}
+
+////
+
+func (s *HashStore) Message(channelID, messageID discord.Snowflake) (*discord.Message, error) {
+	var m discord.Message
+	if err := s.get(s.idKey("messages", channelID), messageID, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Messages returns the messages from the newest to the oldest.
+func (s *HashStore) Messages(channelID discord.Snowflake) ([]discord.Message, error) {
+	var ms []discord.Message
+
+	err := s.getAll(s.idKey("messages", channelID), func(b []byte) error {
+		var m discord.Message
+		if err := json.Unmarshal(b, &m); err != nil {
+			return err
+		}
+
+		ms = append(ms, m)
+		return nil
+	})
+
+	sort.Slice(ms, func(i, j int) bool {
+		return ms[i].ID > ms[j].ID
+	})
+
+	return ms, err
+}
+
+func (s *HashStore) MaxMessages() int {
+	return int(s.HashStoreOptions.MaxMessages)
+}
+
+func (s *HashStore) MessageSet(message *discord.Message) error {
+	var max = s.MaxMessages()
+	if max <= 0 {
+		return nil
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	var key = s.idKey("messages", message.ChannelID)
+
+	var m discord.Message
+	if err := s.get(key, message.ID, &m); err == nil {
+		mergeMessage(&m, message)
+		message = &m
+	}
+
+	if err := s.set(key, message.ID, message); err != nil {
+		return err
+	}
+
+	all, err := s.Backend.HGetAll(key)
+	if err != nil || len(all) <= max {
+		return err
+	}
+
+	// Evict the oldest messages.
+	var ids = make([]discord.Snowflake, 0, len(all))
+	for field := range all {
+		id, err := discord.ParseSnowflake(field)
+		if err != nil {
+			return errors.Wrap(err, "Invalid message ID in "+key)
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] > ids[j]
+	})
+
+	var evict = make([]string, 0, len(ids)-max)
+	for _, id := range ids[max:] {
+		evict = append(evict, id.String())
+	}
+
+	return s.Backend.HDel(key, evict...)
+}
+
+func (s *HashStore) MessageRemove(channelID, messageID discord.Snowflake) error {
+	return s.del(s.idKey("messages", channelID), messageID)
+}
+
+////
+
+func (s *HashStore) Presence(guildID, userID discord.Snowflake) (*discord.Presence, error) {
+	var p discord.Presence
+	if err := s.get(s.idKey("presences", guildID), userID, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (s *HashStore) Presences(guildID discord.Snowflake) ([]discord.Presence, error) {
+	var ps []discord.Presence
+
+	return ps, s.getAll(s.idKey("presences", guildID), func(b []byte) error {
+		var p discord.Presence
+		if err := json.Unmarshal(b, &p); err != nil {
+			return err
+		}
+
+		ps = append(ps, p)
+		return nil
+	})
+}
+
+func (s *HashStore) PresenceSet(guildID discord.Snowflake, presence *discord.Presence) error {
+	return s.set(s.idKey("presences", guildID), presence.User.ID, presence)
+}
+
+func (s *HashStore) PresenceRemove(guildID, userID discord.Snowflake) error {
+	return s.del(s.idKey("presences", guildID), userID)
+}
+
+////
+
+func (s *HashStore) Role(guildID, roleID discord.Snowflake) (*discord.Role, error) {
+	rs, err := s.Roles(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range rs {
+		if r.ID == roleID {
+			return &r, nil
+		}
+	}
+
+	return nil, ErrStoreNotFound
+}
+
+func (s *HashStore) Roles(guildID discord.Snowflake) ([]discord.Role, error) {
+	g, err := s.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.Roles, nil
+}
+
+func (s *HashStore) RoleSet(guildID discord.Snowflake, role *discord.Role) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	g, err := s.Guild(guildID)
+	if err != nil {
+		return err
+	}
+
+	for i, r := range g.Roles {
+		if r.ID == role.ID {
+			g.Roles[i] = *role
+			return s.set(s.key("guilds"), guildID, g)
+		}
+	}
+
+	g.Roles = append(g.Roles, *role)
+	return s.set(s.key("guilds"), guildID, g)
+}
+
+func (s *HashStore) RoleRemove(guildID, roleID discord.Snowflake) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	g, err := s.Guild(guildID)
+	if err != nil {
+		return err
+	}
+
+	for i, r := range g.Roles {
+		if r.ID == roleID {
+			g.Roles = append(g.Roles[:i], g.Roles[i+1:]...)
+			return s.set(s.key("guilds"), guildID, g)
+		}
+	}
+
+	return ErrStoreNotFound
+}
+
+////
+
+func (s *HashStore) VoiceState(guildID, userID discord.Snowflake) (*discord.VoiceState, error) {
+	var vs discord.VoiceState
+	if err := s.get(s.idKey("voicestates", guildID), userID, &vs); err != nil {
+		return nil, err
+	}
+
+	return &vs, nil
+}
+
+func (s *HashStore) VoiceStates(guildID discord.Snowflake) ([]discord.VoiceState, error) {
+	var states []discord.VoiceState
+
+	return states, s.getAll(s.idKey("voicestates", guildID), func(b []byte) error {
+		var vs discord.VoiceState
+		if err := json.Unmarshal(b, &vs); err != nil {
+			return err
+		}
+
+		states = append(states, vs)
+		return nil
+	})
+}
+
+func (s *HashStore) VoiceStateSet(guildID discord.Snowflake, voiceState *discord.VoiceState) error {
+	return s.set(s.idKey("voicestates", guildID), voiceState.UserID, voiceState)
+}
+
+func (s *HashStore) VoiceStateRemove(guildID, userID discord.Snowflake) error {
+	return s.del(s.idKey("voicestates", guildID), userID)
+}
@@ -0,0 +1,207 @@
+package state
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/discord"
+)
+
+// mapBackend is a HashBackend backed by maps, which records the expiries.
+type mapBackend struct {
+	mut     sync.Mutex
+	hashes  map[string]map[string][]byte
+	expires map[string]time.Duration
+}
+
+var _ HashBackend = (*mapBackend)(nil)
+
+func newMapBackend() *mapBackend {
+	return &mapBackend{
+		hashes:  map[string]map[string][]byte{},
+		expires: map[string]time.Duration{},
+	}
+}
+
+func (m *mapBackend) HGet(key, field string) ([]byte, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	b, ok := m.hashes[key][field]
+	if !ok {
+		return nil, ErrStoreNotFound
+	}
+	return b, nil
+}
+
+func (m *mapBackend) HGetAll(key string) (map[string][]byte, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	var all = make(map[string][]byte, len(m.hashes[key]))
+	for field, b := range m.hashes[key] {
+		all[field] = b
+	}
+	return all, nil
+}
+
+func (m *mapBackend) HSet(key, field string, value []byte) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	if m.hashes[key] == nil {
+		m.hashes[key] = map[string][]byte{}
+	}
+	m.hashes[key][field] = value
+	return nil
+}
+
+func (m *mapBackend) HDel(key string, fields ...string) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	for _, field := range fields {
+		delete(m.hashes[key], field)
+	}
+	return nil
+}
+
+func (m *mapBackend) Expire(key string, ttl time.Duration) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	m.expires[key] = ttl
+	return nil
+}
+
+func TestHashStore(t *testing.T) {
+	s := NewHashStore(newMapBackend(), nil)
+
+	if _, err := s.Guild(1); err != ErrStoreNotFound {
+		t.Fatal("Unexpected error for a missing guild:", err)
+	}
+
+	if err := s.GuildSet(&discord.Guild{ID: 1, Name: "guild"}); err != nil {
+		t.Fatal("Failed to set guild:", err)
+	}
+	if err := s.RoleSet(1, &discord.Role{ID: 2, Name: "role"}); err != nil {
+		t.Fatal("Failed to set role:", err)
+	}
+
+	// Roles are kept when the guild is updated without them.
+	if err := s.GuildSet(&discord.Guild{ID: 1, Name: "renamed"}); err != nil {
+		t.Fatal("Failed to update guild:", err)
+	}
+
+	g, err := s.Guild(1)
+	if err != nil {
+		t.Fatal("Failed to get guild:", err)
+	}
+	if g.Name != "renamed" || len(g.Roles) != 1 || g.Roles[0].Name != "role" {
+		t.Fatal("Unexpected guild:", g)
+	}
+
+	if err := s.ChannelSet(&discord.Channel{ID: 3, GuildID: 1, Name: "general"}); err != nil {
+		t.Fatal("Failed to set channel:", err)
+	}
+
+	ch, err := s.Channel(3)
+	if err != nil || ch.Name != "general" || ch.GuildID != 1 {
+		t.Fatal("Unexpected channel:", ch, err)
+	}
+
+	if err := s.ChannelRemove(ch); err != nil {
+		t.Fatal("Failed to remove channel:", err)
+	}
+	if _, err := s.Channel(3); err != ErrStoreNotFound {
+		t.Fatal("Unexpected error for a removed channel:", err)
+	}
+
+	var member = discord.Member{User: discord.User{ID: 4, Username: "hime"}}
+	if err := s.MemberSet(1, &member); err != nil {
+		t.Fatal("Failed to set member:", err)
+	}
+
+	m, err := s.Member(1, 4)
+	if err != nil || m.User.Username != "hime" {
+		t.Fatal("Unexpected member:", m, err)
+	}
+
+	if err := s.MemberRemove(1, 4); err != nil {
+		t.Fatal("Failed to remove member:", err)
+	}
+	if _, err := s.Members(1); err != ErrStoreNotFound {
+		t.Fatal("Unexpected error for no members:", err)
+	}
+}
+
+func TestHashStoreMessages(t *testing.T) {
+	s := NewHashStore(newMapBackend(), &HashStoreOptions{MaxMessages: 2})
+
+	for _, id := range []discord.Snowflake{2, 1, 3} {
+		if err := s.MessageSet(&discord.Message{ID: id, ChannelID: 1}); err != nil {
+			t.Fatal("Failed to set message:", err)
+		}
+	}
+
+	ms, err := s.Messages(1)
+	if err != nil {
+		t.Fatal("Failed to get messages:", err)
+	}
+
+	// The oldest message is evicted, and the rest are from the newest.
+	if len(ms) != 2 || ms[0].ID != 3 || ms[1].ID != 2 {
+		t.Fatal("Unexpected messages:", ms)
+	}
+
+	// Updates are merged into the stored message.
+	if err := s.MessageSet(&discord.Message{ID: 3, ChannelID: 1, Content: "edited"}); err != nil {
+		t.Fatal("Failed to update message:", err)
+	}
+
+	m, err := s.Message(1, 3)
+	if err != nil || m.Content != "edited" {
+		t.Fatal("Unexpected message:", m, err)
+	}
+
+	if err := s.MessageRemove(1, 3); err != nil {
+		t.Fatal("Failed to remove message:", err)
+	}
+	if _, err := s.Message(1, 3); err != ErrStoreNotFound {
+		t.Fatal("Unexpected error for a removed message:", err)
+	}
+}
+
+func TestHashStoreExpire(t *testing.T) {
+	var backend = newMapBackend()
+
+	s := NewHashStore(backend, &HashStoreOptions{
+		Prefix: "bot:",
+		TTL:    time.Hour,
+	})
+
+	if err := s.GuildSet(&discord.Guild{ID: 1}); err != nil {
+		t.Fatal("Failed to set guild:", err)
+	}
+	if err := s.MemberSet(1, &discord.Member{User: discord.User{ID: 2}}); err != nil {
+		t.Fatal("Failed to set member:", err)
+	}
+
+	for _, key := range []string{"bot:guilds", "bot:members:1"} {
+		if ttl := backend.expires[key]; ttl != time.Hour {
+			t.Fatalf("Unexpected TTL for %q: %v", key, ttl)
+		}
+	}
+
+	// No TTL means nothing expires.
+	backend = newMapBackend()
+	s = NewHashStore(backend, nil)
+
+	if err := s.GuildSet(&discord.Guild{ID: 1}); err != nil {
+		t.Fatal("Failed to set guild:", err)
+	}
+	if len(backend.expires) > 0 {
+		t.Fatal("Unexpected expiries:", backend.expires)
+	}
+}
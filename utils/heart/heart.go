@@ -2,6 +2,7 @@
 package heart
 
 import (
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -65,6 +66,22 @@ type Pacemaker struct {
 	// Any callback that returns an error will stop the pacer.
 	Pace func() error
 
+	// Missed, if not nil, is called before a heartbeat if the last one hasn't
+	// been echoed yet.
+	Missed func()
+
+	// Jitter, if true, delays the first heartbeat by a random duration up to
+	// Heartrate, so that connections started together don't beat together.
+	Jitter bool
+
+	// StrictEcho, if true, makes the pacemaker die with ErrDead as soon as a
+	// heartbeat isn't echoed before the next one, right after Missed is
+	// called. Otherwise, it dies only after a few missed heartbeats.
+	StrictEcho bool
+
+	// latency in nanoseconds, guarded by atomic read/writes.
+	latency int64
+
 	stop  atomicStop
 	death chan error
 }
@@ -79,7 +96,19 @@ func NewPacemaker(heartrate time.Duration, pacer func() error) *Pacemaker {
 func (p *Pacemaker) Echo() {
 	// Swap our received heartbeats
 	// p.LastBeat[0], p.LastBeat[1] = time.Now(), p.LastBeat[0]
-	p.EchoBeat.Set(time.Now())
+	var now = time.Now()
+	p.EchoBeat.Set(now)
+
+	// Nothing was sent yet if the time isn't set.
+	if sent := p.SentBeat.Get(); sent > 0 {
+		atomic.StoreInt64(&p.latency, now.UnixNano()-sent)
+	}
+}
+
+// Latency returns the time between the last heartbeat and its echo. It returns
+// 0 if no heartbeats are echoed yet.
+func (p *Pacemaker) Latency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&p.latency))
 }
 
 // Dead, if true, will have Pace return an ErrDead.
@@ -113,11 +142,26 @@ func (p *Pacemaker) Stop() {
 }
 
 func (p *Pacemaker) start() error {
-	// Reset states to its old position.
-	p.EchoBeat.Set(time.Time{})
-	p.SentBeat.Set(time.Time{})
+	// Reset states to its old position. The zero time.Time isn't 0 in
+	// UnixNano, so the timestamps are zeroed directly.
+	atomic.StoreInt64(&p.EchoBeat.unixnano, 0)
+	atomic.StoreInt64(&p.SentBeat.unixnano, 0)
+	atomic.StoreInt64(&p.latency, 0)
+
+	if p.Jitter && p.Heartrate > 0 {
+		delay := time.NewTimer(time.Duration(rand.Int63n(int64(p.Heartrate))))
+		Debug("Delaying the first heartbeat.")
+
+		select {
+		case <-p.stop.Recv():
+			delay.Stop()
+			return nil
+		case <-delay.C:
+		}
+	}
 
-	// Create a new ticker.
+	// Create a new ticker. This is after the jitter, so that the first
+	// heartbeat gets the full duration to be echoed.
 	tick := time.NewTicker(p.Heartrate)
 	defer tick.Stop()
 
@@ -127,15 +171,26 @@ func (p *Pacemaker) start() error {
 	for {
 		Debug("Pacemaker loop restarted.")
 
+		if sent := p.SentBeat.Get(); sent != 0 && p.EchoBeat.Get() < sent {
+			Debug("Last heartbeat was not echoed.")
+
+			if p.Missed != nil {
+				p.Missed()
+			}
+			if p.StrictEcho {
+				return ErrDead
+			}
+		}
+
+		// Save before pacing, in case the echo arrives before Pace returns.
+		p.SentBeat.Set(time.Now())
+
 		if err := p.Pace(); err != nil {
 			return err
 		}
 
 		Debug("Paced.")
 
-		// Paced, save:
-		p.SentBeat.Set(time.Now())
-
 		if p.Dead() {
 			return ErrDead
 		}
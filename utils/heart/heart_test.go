@@ -0,0 +1,44 @@
+package heart
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPacemakerLatency(t *testing.T) {
+	var paced = make(chan time.Duration, 1)
+
+	var p *Pacemaker
+	p = NewPacemaker(time.Hour, func() error {
+		paced <- p.Latency()
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		death := p.StartAsync(nil)
+
+		select {
+		case latency := <-paced:
+			if latency != 0 {
+				t.Fatal("Unexpected latency before an echo:", latency)
+			}
+		case err := <-death:
+			t.Fatal("Pacemaker died:", err)
+		case <-time.After(time.Second):
+			t.Fatal("Pacemaker didn't pace")
+		}
+
+		p.Echo()
+
+		if latency := p.Latency(); latency <= 0 || latency > time.Second {
+			t.Fatal("Unexpected latency after an echo:", latency)
+		}
+
+		// Restarting resets the latency, which is checked in the next round.
+		p.Stop()
+
+		if err := <-death; err != nil {
+			t.Fatal("Unexpected error:", err)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package httputil
 import (
 	"fmt"
 	"strconv"
+
+	"github.com/diamondburned/arikawa/utils/json"
 )
 
 type JSONError struct {
@@ -29,12 +31,67 @@ func (r RequestError) Unwrap() error {
 	return r.err
 }
 
+// HTTPError is returned when Discord responds with a non-2xx status. If the
+// body is a JSON error, Code and Message are filled, as well as Errors for
+// invalid form bodies. Use errors.As to get it:
+//
+//    var herr *httputil.HTTPError
+//    if errors.As(err, &herr) && herr.Code == httputil.CodeUnknownMessage {
+//        // The message was already deleted.
+//    }
+//
 type HTTPError struct {
 	Status int    `json:"-"`
 	Body   []byte `json:"-"`
 
 	Code    ErrorCode `json:"code"`
 	Message string    `json:"message,omitempty"`
+
+	// Errors contains the nested field errors as-is. Use FieldErrors to get
+	// them by field.
+	Errors json.Raw `json:"errors,omitempty"`
+}
+
+// FieldError is a validation error of a single field.
+type FieldError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// FieldErrors returns the field errors keyed by their path, such as
+// "embed.fields.0.name". Nil is returned if there are no field errors.
+func (err HTTPError) FieldErrors() map[string][]FieldError {
+	if len(err.Errors) == 0 {
+		return nil
+	}
+
+	var fields = map[string][]FieldError{}
+	flattenErrors(fields, "", err.Errors)
+	return fields
+}
+
+func flattenErrors(dst map[string][]FieldError, path string, raw json.Raw) {
+	var obj map[string]json.Raw
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return
+	}
+
+	for key, v := range obj {
+		if key == "_errors" {
+			var errs []FieldError
+			if err := json.Unmarshal(v, &errs); err == nil {
+				dst[path] = append(dst[path], errs...)
+			}
+			continue
+		}
+
+		var p = key
+		if path != "" {
+			p = path + "." + key
+		}
+
+		flattenErrors(dst, p, v)
+	}
 }
 
 func (err HTTPError) Error() string {
@@ -56,3 +113,18 @@ func (err HTTPError) Error() string {
 }
 
 type ErrorCode uint
+
+// Some of the error codes documented at
+// https://discordapp.com/developers/docs/topics/opcodes-and-status-codes#json.
+const (
+	CodeUnknownChannel     ErrorCode = 10003
+	CodeUnknownGuild       ErrorCode = 10004
+	CodeUnknownMember      ErrorCode = 10007
+	CodeUnknownMessage     ErrorCode = 10008
+	CodeUnknownRole        ErrorCode = 10011
+	CodeUnknownUser        ErrorCode = 10013
+	CodeMissingAccess      ErrorCode = 50001
+	CodeCannotSendToUser   ErrorCode = 50007
+	CodeMissingPermissions ErrorCode = 50013
+	CodeInvalidFormBody    ErrorCode = 50035
+)
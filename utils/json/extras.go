@@ -0,0 +1,239 @@
+package json
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// CaptureExtras makes the default driver fill the Extras fields of decoded
+// structs with the object keys that the struct has no field for. This allows
+// using fields that Discord added before the library has them. It's disabled
+// by default, as it decodes every object a second time.
+//
+//    json.CaptureExtras = true
+//
+//    s.AddHandler(func(m *gateway.MessageCreateEvent) {
+//        if raw, ok := m.Extras["new_field"]; ok {
+//            log.Println("New field:", raw)
+//        }
+//    })
+//
+// Custom drivers may not support this.
+var CaptureExtras = false
+
+// Extras holds the keys of an object that weren't decoded into any field of
+// its struct. It's filled only if CaptureExtras is true, and is never encoded.
+// The field may also be an *Extras, which keeps the struct comparable; it's
+// then nil if there are no extra keys.
+type Extras map[string]Raw
+
+var (
+	extrasType    = reflect.TypeOf(Extras(nil))
+	extrasPtrType = reflect.TypeOf((*Extras)(nil))
+)
+
+// fillExtras fills the Extras fields in v, which data was decoded into.
+func fillExtras(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if !hasExtras(rv.Type()) {
+		return nil
+	}
+
+	return fillValue(data, rv)
+}
+
+func fillValue(data []byte, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return fillStruct(data, v)
+
+	case reflect.Slice, reflect.Array:
+		if !hasExtras(v.Type().Elem()) {
+			return nil
+		}
+
+		var raws []Raw
+		if err := json.Unmarshal(data, &raws); err != nil {
+			return err
+		}
+
+		for i := 0; i < len(raws) && i < v.Len(); i++ {
+			if err := fillValue(raws[i], v.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func fillStruct(data []byte, v reflect.Value) error {
+	var obj map[string]Raw
+	if err := json.Unmarshal(data, &obj); err != nil || obj == nil {
+		// Not an object, such as a null or a custom encoding.
+		return nil
+	}
+
+	var extras reflect.Value
+	var known = map[string]bool{}
+
+	for _, f := range structFields(v.Type()) {
+		fv := fieldByIndex(v, f.index)
+
+		if f.extras {
+			if !extras.IsValid() && fv.IsValid() {
+				extras = fv
+			}
+			continue
+		}
+
+		known[f.name] = true
+
+		raw, ok := obj[f.name]
+		if !ok || !fv.IsValid() || !hasExtras(f.typ) {
+			continue
+		}
+
+		if err := fillValue(raw, fv); err != nil {
+			return err
+		}
+	}
+
+	if !extras.IsValid() {
+		return nil
+	}
+
+	var leftover = Extras{}
+	for k, raw := range obj {
+		if !known[k] {
+			leftover[k] = raw
+		}
+	}
+
+	if len(leftover) > 0 {
+		if extras.Type() == extrasPtrType {
+			extras.Set(reflect.ValueOf(&leftover))
+		} else {
+			extras.Set(reflect.ValueOf(leftover))
+		}
+	}
+
+	return nil
+}
+
+type field struct {
+	name   string
+	index  []int
+	typ    reflect.Type
+	extras bool
+}
+
+var fieldCache sync.Map // reflect.Type -> []field
+
+// structFields returns the JSON fields of the struct, including the ones of
+// embedded structs.
+func structFields(t reflect.Type) []field {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.([]field)
+	}
+
+	var fields []field
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+
+		if f.Type == extrasType || f.Type == extrasPtrType {
+			if f.PkgPath == "" {
+				fields = append(fields, field{index: []int{i}, extras: true})
+			}
+			continue
+		}
+
+		if f.Anonymous && tag == "" {
+			var embedded = f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				for _, embed := range structFields(embedded) {
+					embed.index = append([]int{i}, embed.index...)
+					fields = append(fields, embed)
+				}
+				continue
+			}
+		}
+
+		if f.PkgPath != "" || tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = f.Name
+		}
+
+		fields = append(fields, field{name: name, index: []int{i}, typ: f.Type})
+	}
+
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+// fieldByIndex is reflect.Value's FieldByIndex, but returns an invalid value
+// instead of panicking on nil embedded pointers.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+var extrasCache sync.Map // reflect.Type -> bool
+
+// hasExtras returns whether values of the type could have an Extras field.
+func hasExtras(t reflect.Type) bool {
+	if has, ok := extrasCache.Load(t); ok {
+		return has.(bool)
+	}
+
+	has := typeHasExtras(t, map[reflect.Type]bool{})
+	extrasCache.Store(t, has)
+	return has
+}
+
+func typeHasExtras(t reflect.Type, visited map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	// Recursive types are only checked once.
+	if t.Kind() != reflect.Struct || visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	for _, f := range structFields(t) {
+		if f.extras || typeHasExtras(f.typ, visited) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -3,6 +3,7 @@
 package json
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
 )
@@ -22,10 +23,26 @@ func (d DefaultDriver) Marshal(v interface{}) ([]byte, error) {
 }
 
 func (d DefaultDriver) Unmarshal(data []byte, v interface{}) error {
-	return json.Unmarshal(data, v)
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	if CaptureExtras {
+		return fillExtras(data, v)
+	}
+
+	return nil
 }
 
 func (d DefaultDriver) DecodeStream(r io.Reader, v interface{}) error {
+	if CaptureExtras {
+		var raw Raw
+		if err := json.NewDecoder(r).Decode(&raw); err != nil {
+			return err
+		}
+		return d.Unmarshal(raw, v)
+	}
+
 	return json.NewDecoder(r).Decode(v)
 }
 
@@ -41,6 +58,22 @@ func Marshal(v interface{}) ([]byte, error) {
 	return Default.Marshal(v)
 }
 
+// MarshalIndent uses the default driver, then indents the output like
+// encoding/json's MarshalIndent.
+func MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	b, err := Default.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, prefix, indent); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
 // Unmarshal uses the default driver.
 func Unmarshal(data []byte, v interface{}) error {
 	return Default.Unmarshal(data, v)
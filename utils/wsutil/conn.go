@@ -6,6 +6,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
@@ -48,6 +49,12 @@ type Conn struct {
 	Conn *websocket.Conn
 	json.Driver
 
+	// Compress, if true, enables zlib-stream transport compression, which
+	// compresses the whole connection instead of each payload. Dial adds the
+	// compress parameter to the URL. Frames that aren't compressed are still
+	// accepted.
+	Compress bool
+
 	dialer *websocket.Dialer
 	events chan Event
 
@@ -58,6 +65,9 @@ type Conn struct {
 	buf  bytes.Buffer
 	zlib io.ReadCloser // (compress/zlib).reader
 
+	// stream is the zlib-stream context, reset on every Dial.
+	stream *zlibStream
+
 	// nil until Dial().
 	closeOnce *sync.Once
 
@@ -91,11 +101,14 @@ func (c *Conn) Dial(ctx context.Context, addr string) error {
 	headers := http.Header{}
 	headers.Set("Accept-Encoding", "zlib")
 
-	// BUG: https://github.com/golang/go/issues/31514
-	// // Enable stream compression:
-	// addr = InjectValues(addr, url.Values{
-	// 	"compress": {"zlib-stream"},
-	// })
+	// Enable stream compression. A new connection has a new zlib context.
+	c.stream = nil
+	if c.Compress {
+		addr = InjectValues(addr, url.Values{
+			"compress": {"zlib-stream"},
+		})
+		c.stream = &zlibStream{}
+	}
 
 	c.Conn, _, err = c.dialer.DialContext(ctx, addr, headers)
 	if err != nil {
@@ -185,6 +198,17 @@ func (c *Conn) handle() ([]byte, error) {
 		return nil, err
 	}
 
+	if t == websocket.BinaryMessage && c.stream != nil {
+		// Part of the zlib-stream; this returns nil until the message is
+		// complete.
+		b, err := readAll(&c.buf, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.stream.write(b)
+	}
+
 	if t == websocket.BinaryMessage {
 		// Probably a zlib payload
 
@@ -57,6 +57,33 @@ func (p *PacemakerLoop) Echo() {
 	p.pacemaker.Echo()
 }
 
+// Latency returns the heartbeat latency, which is 0 if the loop is nil or no
+// heartbeats are echoed yet.
+func (p *PacemakerLoop) Latency() time.Duration {
+	if p == nil {
+		return 0
+	}
+	return p.pacemaker.Latency()
+}
+
+// SetMissed sets the function to be called when a heartbeat isn't echoed
+// before the next one. It must be called before RunAsync.
+func (p *PacemakerLoop) SetMissed(fn func()) {
+	p.pacemaker.Missed = fn
+}
+
+// SetJitter sets whether the first heartbeat is delayed by a random duration
+// up to the heartrate. It must be called before RunAsync.
+func (p *PacemakerLoop) SetJitter(jitter bool) {
+	p.pacemaker.Jitter = jitter
+}
+
+// SetStrictEcho sets whether the loop dies as soon as a heartbeat isn't echoed
+// before the next one. It must be called before RunAsync.
+func (p *PacemakerLoop) SetStrictEcho(strict bool) {
+	p.pacemaker.StrictEcho = strict
+}
+
 func (p *PacemakerLoop) Stop() {
 	p.pacemaker.Stop()
 }
@@ -0,0 +1,89 @@
+package wsutil
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// zlibSuffix is the end of a sync flush, which ends every complete message of a
+// zlib-stream.
+var zlibSuffix = []byte{0x00, 0x00, 0xff, 0xff}
+
+// zlibWindow is the size of the deflate window, which is as far back as a
+// message could refer to.
+const zlibWindow = 32 * 1024
+
+// zlibStream decompresses a zlib-stream, which is a single zlib context shared
+// by all messages of the connection. A message could be split across frames,
+// and only its last frame ends with zlibSuffix.
+//
+// flate's reader can't continue after its input runs out, so each message is
+// inflated by a reset reader instead, with the last window of the output as the
+// dictionary. This works because messages always end on a block boundary after
+// the sync flush.
+type zlibStream struct {
+	in     bytes.Buffer
+	out    bytes.Buffer
+	window []byte
+	flate  io.ReadCloser
+	// header is true once the zlib header at the start of the stream is read.
+	header bool
+}
+
+// write writes a compressed frame. It returns the decompressed message, or nil
+// if the message isn't complete yet.
+func (z *zlibStream) write(p []byte) ([]byte, error) {
+	z.in.Write(p)
+
+	if !bytes.HasSuffix(z.in.Bytes(), zlibSuffix) {
+		return nil, nil
+	}
+
+	defer z.in.Reset()
+	var src = z.in.Bytes()
+
+	if !z.header {
+		// The header is 2 bytes: deflate with no preset dictionary, with a
+		// checksum that's a multiple of 31.
+		if len(src) < 2 || src[0]&0x0f != 8 || src[1]&0x20 != 0 ||
+			(uint16(src[0])<<8|uint16(src[1]))%31 != 0 {
+
+			return nil, errors.New("Invalid zlib-stream header")
+		}
+
+		src = src[2:]
+		z.header = true
+	}
+
+	r := bytes.NewReader(src)
+
+	if z.flate == nil {
+		z.flate = flate.NewReaderDict(r, z.window)
+	} else if err := z.flate.(flate.Resetter).Reset(r, z.window); err != nil {
+		return nil, errors.Wrap(err, "Failed to reset flate reader")
+	}
+
+	defer z.out.Reset()
+
+	// The reader runs out of input after the sync flush, which is expected.
+	_, err := z.out.ReadFrom(z.flate)
+	if err != nil && (err != io.ErrUnexpectedEOF || r.Len() > 0) {
+		return nil, errors.Wrap(err, "Failed to inflate")
+	}
+
+	var b = z.out.Bytes()
+
+	// Keep the last window of the output for the next message.
+	z.window = append(z.window, b...)
+	if n := len(z.window) - zlibWindow; n > 0 {
+		z.window = append(z.window[:0], z.window[n:]...)
+	}
+
+	cpy := make([]byte, len(b))
+	copy(cpy, b)
+
+	return cpy, nil
+}
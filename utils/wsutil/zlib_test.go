@@ -0,0 +1,131 @@
+package wsutil
+
+import (
+	"bytes"
+	"compress/zlib"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// zlibWriter compresses messages the way Discord's zlib-stream does, with a
+// sync flush after each message.
+type zlibWriter struct {
+	buf bytes.Buffer
+	w   *zlib.Writer
+}
+
+func newZlibWriter() *zlibWriter {
+	var z zlibWriter
+	z.w = zlib.NewWriter(&z.buf)
+	return &z
+}
+
+func (z *zlibWriter) message(t *testing.T, msg string) []byte {
+	z.buf.Reset()
+
+	if _, err := z.w.Write([]byte(msg)); err != nil {
+		t.Fatal("Failed to compress:", err)
+	}
+	if err := z.w.Flush(); err != nil {
+		t.Fatal("Failed to flush:", err)
+	}
+
+	return append([]byte(nil), z.buf.Bytes()...)
+}
+
+func TestZlibStream(t *testing.T) {
+	var w = newZlibWriter()
+	var z zlibStream
+
+	var msgs = []string{
+		`{"op":10,"d":{"heartbeat_interval":41250}}`,
+		`{"op":11}`,
+		strings.Repeat(`{"op":0,"t":"MESSAGE_CREATE"}`, 100),
+	}
+
+	for _, msg := range msgs {
+		b, err := z.write(w.message(t, msg))
+		if err != nil {
+			t.Fatal("Failed to decompress:", err)
+		}
+		if string(b) != msg {
+			t.Fatalf("Unexpected message: %q", b)
+		}
+	}
+}
+
+func TestZlibStreamSplit(t *testing.T) {
+	var w = newZlibWriter()
+	var z zlibStream
+
+	var msg = strings.Repeat(`{"op":0,"t":"GUILD_CREATE"}`, 50)
+	var frame = w.message(t, msg)
+
+	// Only the last frame completes the message.
+	for _, part := range [][]byte{frame[:2], frame[2 : len(frame)/2]} {
+		b, err := z.write(part)
+		if err != nil || b != nil {
+			t.Fatalf("Unexpected result for a partial frame: %q, %v", b, err)
+		}
+	}
+
+	b, err := z.write(frame[len(frame)/2:])
+	if err != nil {
+		t.Fatal("Failed to decompress:", err)
+	}
+	if string(b) != msg {
+		t.Fatalf("Unexpected message: %q", b)
+	}
+}
+
+func TestZlibStreamWindow(t *testing.T) {
+	var w = newZlibWriter()
+	var z zlibStream
+
+	// Random text doesn't compress by itself, so the second message is only
+	// small if it's compressed into back-references to the first.
+	var r = rand.New(rand.NewSource(0))
+	var msg = make([]byte, 1000)
+	for i := range msg {
+		msg[i] = 'a' + byte(r.Intn(26))
+	}
+
+	first := w.message(t, string(msg))
+	second := w.message(t, string(msg))
+
+	if len(second) >= len(first)/2 {
+		t.Fatal("Expected the second message to refer back to the first")
+	}
+
+	for _, frame := range [][]byte{first, second} {
+		b, err := z.write(frame)
+		if err != nil {
+			t.Fatal("Failed to decompress:", err)
+		}
+		if !bytes.Equal(b, msg) {
+			t.Fatalf("Unexpected message: %q", b)
+		}
+	}
+
+	// Only the end of a message longer than the window is kept.
+	if _, err := z.write(w.message(t, strings.Repeat("arikawa ", 5000))); err != nil {
+		t.Fatal("Failed to decompress:", err)
+	}
+	if len(z.window) != zlibWindow {
+		t.Fatal("Unexpected window size:", len(z.window))
+	}
+}
+
+func TestZlibStreamHeader(t *testing.T) {
+	var w = newZlibWriter()
+	var z zlibStream
+
+	var frame = w.message(t, `{"op":11}`)
+	frame[0] = 0x00
+
+	_, err := z.write(frame)
+	if err == nil || err.Error() != "Invalid zlib-stream header" {
+		t.Fatal("Unexpected error:", err)
+	}
+}
@@ -2,6 +2,25 @@
 // as managing and keeping track of multiple voice sessions.
 //
 // This package abstracts the subpackage voice/voicesession and voice/udp.
+//
+// Joining a channel and sending Opus frames looks like this:
+//
+//    v := voice.NewVoice(s)
+//
+//    vs, err := v.JoinChannel(guildID, channelID, false, false)
+//    if err != nil {
+//        return err
+//    }
+//    defer vs.Disconnect()
+//
+//    vs.Speaking(voicegateway.Microphone)
+//    defer vs.StopSpeaking()
+//
+//    // Each write is a single Opus frame.
+//    vs.Write(frame)
+//
+// The voice handshake is done by a Voice instead of the session package, as
+// it needs the state to know the current user's voice state.
 package voice
 
 import (